@@ -11,28 +11,38 @@ import (
 func main() {
 	kingpin.Version("gitbatch version 0.5.3")
 
-	dirs := kingpin.Flag("directory", "Directory(s) to roam for git repositories.").Short('d').Strings()
-	mode := kingpin.Flag("mode", "Application start mode, more sensible with quick run.").Short('m').String()
-	recursionDepth := kingpin.Flag("recursive-depth", "Find directories recursively.").Default("0").Short('r').Int()
-	logLevel := kingpin.Flag("log-level", "Logging level; trace,debug,info,warn,error").Default("error").Short('l').String()
-	quick := kingpin.Flag("quick", "runs without gui and fetches/pull remote upstream.").Short('q').Bool()
+	// the flags fill the configuration directly, the unset ones are read from
+	// the configuration file
+	c := &app.Config{}
+	kingpin.Flag("directory", "Directory(s) to roam for git repositories.").Short('d').StringsVar(&c.Directories)
+	kingpin.Flag("mode", "Application start mode, more sensible with quick run.").Short('m').StringVar(&c.Mode)
+	kingpin.Flag("recursive-depth", "Find directories recursively.").Default("0").Short('r').IntVar(&c.Depth)
+	kingpin.Flag("log-level", "Logging level; trace,debug,info,warn,error").Default("error").Short('l').StringVar(&c.LogLevel)
+	kingpin.Flag("quick", "Runs the job of the mode without gui.").Short('q').BoolVar(&c.QuickMode)
+	kingpin.Flag("timeout", "Maximum duration of a single job, e.g. 30s or 2m. Zero means no limit.").Default("0s").DurationVar(&c.Timeout)
+	kingpin.Flag("concurrency", "Maximum number of parallel jobs. Zero means the number of CPUs.").Default("0").Short('j').IntVar(&c.Concurrency)
+	kingpin.Flag("host-concurrency", "Maximum number of parallel jobs against the same remote host. Zero means no limit.").Default("0").IntVar(&c.HostConcurrency)
+	kingpin.Flag("load-concurrency", "Maximum number of repositories loaded in parallel. Zero means the number of CPUs.").Default("0").IntVar(&c.LoadConcurrency)
+	kingpin.Flag("branch", "Target branch of the checkout mode.").Short('b').StringVar(&c.Branch)
+	kingpin.Flag("sync", "Clones the missing repositories of the workspace manifest and loads them.").BoolVar(&c.Sync)
+	kingpin.Flag("manifest", "Path of the workspace manifest.").StringVar(&c.Manifest)
+	kingpin.Flag("retry", "Maximum attempts of a job failing with a transient network error.").Default("0").IntVar(&c.RetryAttempts)
+	kingpin.Flag("auto-fetch", "Interval of the background fetch in gui, e.g. 5m. Zero disables it.").Default("0s").DurationVar(&c.AutoFetchInterval)
+	kingpin.Flag("rebase", "Rebases the local commits onto the upstream on pull instead of merging.").BoolVar(&c.Rebase)
+	kingpin.Flag("autostash", "Stashes the local changes before a rebase and applies them afterwards.").BoolVar(&c.AutoStash)
+	kingpin.Flag("ff-only", "Pulls and merges only if the branch can be fast-forwarded, diverged branches are skipped.").BoolVar(&c.FFOnly)
+	kingpin.Flag("output", "Output format of the quick mode; text, json or ndjson.").Short('o').EnumVar(&c.Output, "text", "json", "ndjson")
 
 	kingpin.Parse()
 
-	if err := run(*dirs, *logLevel, *recursionDepth, *quick, *mode); err != nil {
+	if err := run(c); err != nil {
 		fmt.Fprintf(os.Stderr, "application quitted with an unhandled error: %v", err)
 		os.Exit(1)
 	}
 }
 
-func run(dirs []string, log string, depth int, quick bool, mode string) error {
-	app, err := app.New(&app.Config{
-		Directories: dirs,
-		LogLevel:    log,
-		Depth:       depth,
-		QuickMode:   quick,
-		Mode:        mode,
-	})
+func run(c *app.Config) error {
+	app, err := app.New(c)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,26 @@
+package app
+
+// autoFetchDirectories returns the directories of the repositories that are
+// fetched in the background. A directory is included if there is no include
+// pattern or it matches one of them, and it doesn't match any exclude pattern.
+func autoFetchDirectories(directories, include, exclude []string) map[string]bool {
+	matchAny := func(patterns []string, dir string) bool {
+		for _, pattern := range patterns {
+			if matchDirectory(pattern, dir) {
+				return true
+			}
+		}
+		return false
+	}
+	selected := make(map[string]bool)
+	for _, dir := range directories {
+		if len(include) > 0 && !matchAny(include, dir) {
+			continue
+		}
+		if matchAny(exclude, dir) {
+			continue
+		}
+		selected[dir] = true
+	}
+	return selected
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"testing"
+)
+
+func TestAutoFetchDirectories(t *testing.T) {
+	directories := []string{
+		"/src/backend/api",
+		"/src/backend/billing",
+		"/src/web/dashboard",
+	}
+	var tests = []struct {
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{nil, nil, directories},
+		{[]string{"/src/backend/*"}, nil, []string{"/src/backend/api", "/src/backend/billing"}},
+		{nil, []string{"bill*"}, []string{"/src/backend/api", "/src/web/dashboard"}},
+		{[]string{"/src/backend/*"}, []string{"billing"}, []string{"/src/backend/api"}},
+	}
+	for _, test := range tests {
+		output := autoFetchDirectories(directories, test.include, test.exclude)
+		if len(output) != len(test.expected) {
+			t.Errorf("Test Failed. include: %v, exclude: %v, output: %v, expected: %v", test.include, test.exclude, output, test.expected)
+			continue
+		}
+		for _, dir := range test.expected {
+			if !output[dir] {
+				t.Errorf("Test Failed. include: %v, exclude: %v, %s is not selected", test.include, test.exclude, dir)
+			}
+		}
+	}
+}
@@ -3,8 +3,13 @@ package app
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/isacikgoz/gitbatch/internal/command"
+	"github.com/isacikgoz/gitbatch/internal/git"
 	"github.com/isacikgoz/gitbatch/internal/gui"
+	"github.com/isacikgoz/gitbatch/internal/history"
+	"github.com/isacikgoz/gitbatch/internal/job"
 )
 
 // The App struct is responsible to hold app-wide related entities. Currently
@@ -20,6 +25,79 @@ type Config struct {
 	Depth       int
 	QuickMode   bool
 	Mode        string
+	Timeout     time.Duration
+	// Concurrency is the maximum number of parallel jobs, zero means the
+	// number of logical CPUs
+	Concurrency int
+	// HostConcurrency is the maximum number of parallel jobs against the same
+	// remote host, zero means no limit
+	HostConcurrency int
+	// LoadConcurrency is the maximum number of repositories loaded in
+	// parallel, zero means the number of logical CPUs. Loading is local, so
+	// it can be wider than the jobs
+	LoadConcurrency int
+	// Output is the format of the quick mode output; text, json or ndjson
+	Output string
+	// Branch is the target branch of the checkout mode
+	Branch string
+	// Manifest is the path of the workspace manifest
+	Manifest string
+	// Sync clones the missing repositories of the manifest before loading
+	Sync bool
+	// Groups are the named sets of repository path patterns
+	Groups map[string][]string
+	// AutoFetchInterval is the period of the background fetch, zero
+	// disables it
+	AutoFetchInterval time.Duration
+	// AutoFetchInclude and AutoFetchExclude are the path patterns of the
+	// repositories to be fetched in the background, all by default
+	AutoFetchInclude []string
+	AutoFetchExclude []string
+	// Tokens are the access token credentials of the hosts
+	Tokens map[string]*git.Credentials
+	// RetryAttempts is the maximum number of attempts of a job that fails with
+	// a transient network error, one means no retry
+	RetryAttempts int
+	// RetryBackoff is the delay before the first retry, it is doubled on
+	// every retry
+	RetryBackoff time.Duration
+	// History is the path of the operation log, empty disables it
+	History string
+	// Rebase makes the pull rebase the local commits onto the upstream
+	// instead of merging it
+	Rebase bool
+	// AutoStash stashes the local changes before a rebase and applies them
+	// afterwards
+	AutoStash bool
+	// FFOnly makes the pull and merge refuse to create merge commits, the
+	// diverged branches are skipped
+	FFOnly bool
+	// StatusMode is the implementation of the status; "git" runs the git
+	// command, "go-git" reads the worktree natively
+	StatusMode string
+	// CleanupBase is the branch that the stale branches are merged into, the
+	// default branch of the remote if it is empty
+	CleanupBase string
+}
+
+// maxRetryBackoff limits the delay between the retries of a job
+const maxRetryBackoff = 30 * time.Second
+
+// retryPolicy returns the retry policy of the jobs
+func (c *Config) retryPolicy() job.RetryPolicy {
+	return job.RetryPolicy{
+		MaxAttempts: c.RetryAttempts,
+		Backoff:     c.RetryBackoff,
+		MaxBackoff:  maxRetryBackoff,
+	}
+}
+
+// history returns the operation log, nil if it is disabled
+func (c *Config) history() *history.Log {
+	if len(c.History) == 0 {
+		return nil
+	}
+	return history.New(c.History)
 }
 
 // New will handle pre-required operations. It is designed to be a wrapper for
@@ -42,12 +120,48 @@ func New(argConfig *Config) (*App, error) {
 
 // Run starts the application.
 func (a *App) Run() error {
+	for host, c := range a.Config.Tokens {
+		git.SetToken(host, c)
+	}
+	if len(a.Config.StatusMode) > 0 {
+		if err := command.SetStatusMode(a.Config.StatusMode); err != nil {
+			return err
+		}
+	}
 	dirs := generateDirectories(a.Config.Directories, a.Config.Depth)
+	m, err := loadManifest(a.Config.Manifest)
+	if err != nil {
+		return err
+	}
+	if a.Config.Sync {
+		if m == nil {
+			return fmt.Errorf("there is no manifest at %s", a.Config.Manifest)
+		}
+		dirs = appendDirectories(dirs, syncManifest(os.Stderr, m, a.Config)...)
+	}
 	if a.Config.QuickMode {
 		return a.execQuickMode(dirs)
 	}
 	// create a gui.Gui struct and run the gui
-	gui, err := gui.New(a.Config.Mode, dirs)
+	gui, err := gui.New(&gui.Options{
+		Mode:        a.Config.Mode,
+		Directories: dirs,
+		JobTimeout:  a.Config.Timeout,
+		Retry:       a.Config.retryPolicy(),
+		History:     a.Config.history(),
+		Rebase:      a.Config.Rebase,
+		AutoStash:   a.Config.AutoStash,
+		FFOnly:      a.Config.FFOnly,
+		CleanupBase: a.Config.CleanupBase,
+
+		Concurrency:     a.Config.Concurrency,
+		HostConcurrency: a.Config.HostConcurrency,
+		LoadConcurrency: a.Config.LoadConcurrency,
+		Groups:          groupDirectories(dirs, a.Config.Groups, m),
+
+		AutoFetchInterval: a.Config.AutoFetchInterval,
+		AutoFetch:         autoFetchDirectories(dirs, a.Config.AutoFetchInclude, a.Config.AutoFetchExclude),
+	})
 	if err != nil {
 		return err
 	}
@@ -70,13 +184,54 @@ func overrideConfig(appConfig, setupConfig *Config) *Config {
 	if len(setupConfig.Mode) > 0 {
 		appConfig.Mode = setupConfig.Mode
 	}
+	if setupConfig.Timeout > 0 {
+		appConfig.Timeout = setupConfig.Timeout
+	}
+	if setupConfig.Concurrency > 0 {
+		appConfig.Concurrency = setupConfig.Concurrency
+	}
+	if setupConfig.HostConcurrency > 0 {
+		appConfig.HostConcurrency = setupConfig.HostConcurrency
+	}
+	if setupConfig.LoadConcurrency > 0 {
+		appConfig.LoadConcurrency = setupConfig.LoadConcurrency
+	}
+	if len(setupConfig.Output) > 0 {
+		appConfig.Output = setupConfig.Output
+	}
+	if len(setupConfig.Branch) > 0 {
+		appConfig.Branch = setupConfig.Branch
+	}
+	if len(setupConfig.Manifest) > 0 {
+		appConfig.Manifest = setupConfig.Manifest
+	}
+	if setupConfig.Sync {
+		appConfig.Sync = setupConfig.Sync
+	}
+	if setupConfig.Rebase {
+		appConfig.Rebase = setupConfig.Rebase
+	}
+	if setupConfig.AutoStash {
+		appConfig.AutoStash = setupConfig.AutoStash
+	}
+	if setupConfig.FFOnly {
+		appConfig.FFOnly = setupConfig.FFOnly
+	}
+	if len(setupConfig.CleanupBase) > 0 {
+		appConfig.CleanupBase = setupConfig.CleanupBase
+	}
+	if len(setupConfig.StatusMode) > 0 {
+		appConfig.StatusMode = setupConfig.StatusMode
+	}
+	if setupConfig.RetryAttempts > 0 {
+		appConfig.RetryAttempts = setupConfig.RetryAttempts
+	}
+	if setupConfig.AutoFetchInterval > 0 {
+		appConfig.AutoFetchInterval = setupConfig.AutoFetchInterval
+	}
 	return appConfig
 }
 
 func (a *App) execQuickMode(directories []string) error {
-	if a.Config.Mode != "fetch" && a.Config.Mode != "pull" {
-		return fmt.Errorf("unrecognized quick mode: " + a.Config.Mode)
-	}
-	quick(directories, a.Config.Mode)
-	return nil
+	return quick(os.Stdout, directories, a.Config)
 }
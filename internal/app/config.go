@@ -17,19 +17,52 @@ var (
 
 	configurationDirectory = filepath.Join(osConfigDirectory(runtime.GOOS), appName)
 	configFileAbsPath      = filepath.Join(configurationDirectory, configFileName)
+	manifestFileAbsPath    = filepath.Join(configurationDirectory, "manifest"+configFileExt)
+	historyFileAbsPath     = filepath.Join(configurationDirectory, "history.jsonl")
 )
 
 // configuration items
 var (
-	modeKey             = "mode"
-	modeKeyDefault      = "fetch"
-	pathsKey            = "paths"
-	pathsKeyDefault     = []string{"."}
-	logLevelKeyDefault  = "error"
-	quickKey            = "quick"
-	quickKeyDefault     = false
-	recursionKey        = "recursion"
-	recursionKeyDefault = 1
+	modeKey                     = "mode"
+	modeKeyDefault              = "fetch"
+	pathsKey                    = "paths"
+	pathsKeyDefault             = []string{"."}
+	logLevelKeyDefault          = "error"
+	quickKey                    = "quick"
+	quickKeyDefault             = false
+	recursionKey                = "recursion"
+	recursionKeyDefault         = 1
+	timeoutKey                  = "timeout"
+	timeoutKeyDefault           = "0s"
+	concurrencyKey              = "concurrency"
+	concurrencyKeyDefault       = 0
+	hostConcurrencyKey          = "hostconcurrency"
+	hostConcurrencyKeyDefault   = 0
+	loadConcurrencyKey          = "loadconcurrency"
+	loadConcurrencyKeyDefault   = 0
+	outputKey                   = "output"
+	outputKeyDefault            = "text"
+	manifestKey                 = "manifest"
+	groupsKey                   = "groups"
+	autoFetchIntervalKey        = "autofetch.interval"
+	autoFetchIntervalKeyDefault = "0s"
+	autoFetchIncludeKey         = "autofetch.include"
+	autoFetchExcludeKey         = "autofetch.exclude"
+	tokensKey                   = "tokens"
+	retryAttemptsKey            = "retry.attempts"
+	retryAttemptsKeyDefault     = 3
+	retryBackoffKey             = "retry.backoff"
+	retryBackoffKeyDefault      = "1s"
+	historyKey                  = "history"
+	rebaseKey                   = "rebase"
+	rebaseKeyDefault            = false
+	autoStashKey                = "autostash"
+	autoStashKeyDefault         = false
+	ffOnlyKey                   = "ffonly"
+	ffOnlyKeyDefault            = false
+	statusKey                   = "status"
+	statusKeyDefault            = "git"
+	cleanupBaseKey              = "cleanup.base"
 )
 
 // loadConfiguration returns a Config struct is filled
@@ -43,6 +76,10 @@ func loadConfiguration() (*Config, error) {
 	if err := readConfiguration(); err != nil {
 		return nil, err
 	}
+	tokens, err := loadTokens()
+	if err != nil {
+		return nil, err
+	}
 	var directories []string
 	if len(viper.GetStringSlice(pathsKey)) <= 0 {
 		d, _ := os.Getwd()
@@ -55,6 +92,27 @@ func loadConfiguration() (*Config, error) {
 		Depth:       viper.GetInt(recursionKey),
 		QuickMode:   viper.GetBool(quickKey),
 		Mode:        viper.GetString(modeKey),
+		Timeout:     viper.GetDuration(timeoutKey),
+
+		Concurrency:     viper.GetInt(concurrencyKey),
+		HostConcurrency: viper.GetInt(hostConcurrencyKey),
+		LoadConcurrency: viper.GetInt(loadConcurrencyKey),
+		Output:          viper.GetString(outputKey),
+		Manifest:        viper.GetString(manifestKey),
+		Groups:          viper.GetStringMapStringSlice(groupsKey),
+
+		AutoFetchInterval: viper.GetDuration(autoFetchIntervalKey),
+		AutoFetchInclude:  viper.GetStringSlice(autoFetchIncludeKey),
+		AutoFetchExclude:  viper.GetStringSlice(autoFetchExcludeKey),
+		Tokens:            tokens,
+		RetryAttempts:     viper.GetInt(retryAttemptsKey),
+		RetryBackoff:      viper.GetDuration(retryBackoffKey),
+		History:           viper.GetString(historyKey),
+		Rebase:            viper.GetBool(rebaseKey),
+		AutoStash:         viper.GetBool(autoStashKey),
+		FFOnly:            viper.GetBool(ffOnlyKey),
+		StatusMode:        viper.GetString(statusKey),
+		CleanupBase:       viper.GetString(cleanupBaseKey),
 	}
 	return config, nil
 }
@@ -64,6 +122,20 @@ func setDefaults() error {
 	viper.SetDefault(quickKey, quickKeyDefault)
 	viper.SetDefault(recursionKey, recursionKeyDefault)
 	viper.SetDefault(modeKey, modeKeyDefault)
+	viper.SetDefault(timeoutKey, timeoutKeyDefault)
+	viper.SetDefault(concurrencyKey, concurrencyKeyDefault)
+	viper.SetDefault(hostConcurrencyKey, hostConcurrencyKeyDefault)
+	viper.SetDefault(loadConcurrencyKey, loadConcurrencyKeyDefault)
+	viper.SetDefault(outputKey, outputKeyDefault)
+	viper.SetDefault(manifestKey, manifestFileAbsPath)
+	viper.SetDefault(autoFetchIntervalKey, autoFetchIntervalKeyDefault)
+	viper.SetDefault(retryAttemptsKey, retryAttemptsKeyDefault)
+	viper.SetDefault(retryBackoffKey, retryBackoffKeyDefault)
+	viper.SetDefault(historyKey, historyFileAbsPath)
+	viper.SetDefault(rebaseKey, rebaseKeyDefault)
+	viper.SetDefault(autoStashKey, autoStashKeyDefault)
+	viper.SetDefault(ffOnlyKey, ffOnlyKeyDefault)
+	viper.SetDefault(statusKey, statusKeyDefault)
 	// viper.SetDefault(pathsKey, pathsKeyDefault)
 	return nil
 }
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// groupDirectories assigns the directories to groups. A directory belongs to a
+// configured group if it matches one of the group's patterns or it is listed
+// under the group in the manifest. Directories without any group belong to the
+// group named after their parent directory. Patterns with a path separator are
+// matched against the whole path, others against the name of the directory.
+func groupDirectories(directories []string, groups map[string][]string, m *manifest) map[string][]string {
+	assigned := make(map[string][]string)
+	add := func(dir, group string) {
+		for _, g := range assigned[dir] {
+			if g == group {
+				return
+			}
+		}
+		assigned[dir] = append(assigned[dir], group)
+	}
+	for _, dir := range directories {
+		for group, patterns := range groups {
+			for _, pattern := range patterns {
+				if matchDirectory(pattern, dir) {
+					add(dir, group)
+					break
+				}
+			}
+		}
+	}
+	if m != nil {
+		for _, e := range m.Repositories {
+			for _, group := range e.Groups {
+				add(filepath.Clean(e.Path), group)
+			}
+		}
+	}
+	for _, dir := range directories {
+		if len(assigned[dir]) == 0 {
+			add(dir, filepath.Base(filepath.Dir(dir)))
+		}
+		sort.Strings(assigned[dir])
+	}
+	return assigned
+}
+
+// matchDirectory reports whether the directory matches the group pattern
+func matchDirectory(pattern, dir string) bool {
+	if !strings.ContainsRune(pattern, filepath.Separator) {
+		ok, _ := filepath.Match(pattern, filepath.Base(dir))
+		return ok
+	}
+	pattern, err := expandPath(pattern)
+	if err != nil {
+		return false
+	}
+	ok, _ := filepath.Match(filepath.Clean(pattern), filepath.Clean(dir))
+	return ok
+}
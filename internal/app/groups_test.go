@@ -0,0 +1,45 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupDirectories(t *testing.T) {
+	directories := []string{
+		"/src/backend/api",
+		"/src/backend/billing",
+		"/src/web/dashboard",
+		"/src/tools/gitbatch",
+	}
+	groups := map[string][]string{
+		"backend":  {"/src/backend/*"},
+		"frontend": {"dashboard"},
+		"billing":  {"bill*"},
+	}
+	m := &manifest{
+		Repositories: []*manifestEntry{
+			{URL: "git@github.com:isacikgoz/gitbatch.git", Path: "/src/tools/gitbatch", Groups: []string{"infra"}},
+		},
+	}
+	output := groupDirectories(directories, groups, m)
+	var tests = []struct {
+		input    string
+		expected []string
+	}{
+		{"/src/backend/api", []string{"backend"}},
+		{"/src/backend/billing", []string{"backend", "billing"}},
+		{"/src/web/dashboard", []string{"frontend"}},
+		{"/src/tools/gitbatch", []string{"infra"}},
+	}
+	for _, test := range tests {
+		if !reflect.DeepEqual(output[test.input], test.expected) {
+			t.Errorf("Test Failed. %s inputted, output: %v, expected: %v", test.input, output[test.input], test.expected)
+		}
+	}
+	// without any configuration, parent directory is the group
+	output = groupDirectories(directories, nil, nil)
+	if g := output["/src/web/dashboard"]; len(g) != 1 || g[0] != "web" {
+		t.Errorf("Test Failed. output: %v, expected: [web]", g)
+	}
+}
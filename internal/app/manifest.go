@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/semaphore"
+)
+
+// manifest declares the repositories of a workspace, the missing ones can be
+// cloned with the sync mode
+type manifest struct {
+	// Root is the directory that relative paths are resolved against,
+	// defaults to the working directory
+	Root         string           `mapstructure:"root"`
+	Repositories []*manifestEntry `mapstructure:"repositories"`
+}
+
+// manifestEntry is a single repository of the workspace
+type manifestEntry struct {
+	URL string `mapstructure:"url"`
+	// Path is where the repository lives, defaults to the name of the
+	// repository under the root
+	Path string `mapstructure:"path"`
+	// Branch is checked out after clone, remote's default branch if empty
+	Branch string   `mapstructure:"branch"`
+	Groups []string `mapstructure:"groups"`
+}
+
+// loadManifest reads the manifest at given path and resolves the paths of the
+// entries. A missing manifest is not an error, nil is returned for it
+func loadManifest(path string) (*manifest, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType(configType)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := v.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	root, err := expandPath(m.Root)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range m.Repositories {
+		if len(e.URL) == 0 {
+			return nil, fmt.Errorf("repository %d of the manifest has no url", i+1)
+		}
+		if len(e.Path) == 0 {
+			e.Path = repositoryName(e.URL)
+		}
+		if e.Path, err = expandPath(e.Path); err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(e.Path) {
+			if e.Path, err = filepath.Abs(filepath.Join(root, e.Path)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// syncManifest clones the missing repositories of the manifest in parallel and
+// returns the paths of the repositories that are on the disk afterwards
+func syncManifest(w io.Writer, m *manifest, c *Config) []string {
+	maxWorkers := c.Concurrency
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0)
+	}
+	var (
+		sem   = semaphore.NewWeighted(int64(maxWorkers))
+		wg    sync.WaitGroup
+		mx    sync.Mutex
+		ready = make([]bool, len(m.Repositories))
+	)
+	for i, e := range m.Repositories {
+		if _, err := os.Stat(filepath.Join(e.Path, ".git")); err == nil {
+			ready[i] = true
+			continue
+		}
+		wg.Add(1)
+		go func(i int, e *manifestEntry) {
+			defer wg.Done()
+			sem.Acquire(context.Background(), 1)
+			defer sem.Release(1)
+			ctx := context.Background()
+			if c.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+				defer cancel()
+			}
+			err := command.Clone(ctx, &command.CloneOptions{
+				URL:         e.URL,
+				Path:        e.Path,
+				Branch:      e.Branch,
+				CommandMode: command.ModeNative,
+			})
+			mx.Lock()
+			defer mx.Unlock()
+			if err != nil {
+				fmt.Fprintf(w, "could not clone %s into %s: %s\n", e.URL, e.Path, err)
+				return
+			}
+			fmt.Fprintf(w, "%s: cloned\n", e.Path)
+			ready[i] = true
+		}(i, e)
+	}
+	wg.Wait()
+	paths := make([]string, 0)
+	for i, e := range m.Repositories {
+		if ready[i] {
+			paths = append(paths, e.Path)
+		}
+	}
+	return paths
+}
+
+// appendDirectories adds the paths to directories unless they are already in
+func appendDirectories(directories []string, paths ...string) []string {
+	known := make(map[string]bool)
+	for _, d := range directories {
+		known[filepath.Clean(d)] = true
+	}
+	for _, p := range paths {
+		if !known[filepath.Clean(p)] {
+			known[filepath.Clean(p)] = true
+			directories = append(directories, p)
+		}
+	}
+	return directories
+}
+
+// repositoryName returns the name of the repository from its url e.g. gitbatch
+// for git@github.com:isacikgoz/gitbatch.git
+func repositoryName(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// expandPath replaces the leading ~ with the home directory, an empty path is
+// the working directory
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if len(path) == 0 {
+		return os.Getwd()
+	}
+	return path, nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestLoadManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "manifest.yml")
+	content := "root: " + dir + "\n" +
+		"repositories:\n" +
+		"  - url: git@github.com:isacikgoz/gitbatch.git\n" +
+		"    branch: master\n" +
+		"    groups: [tools]\n" +
+		"  - url: https://github.com/isacikgoz/gia.git\n" +
+		"    path: nested/gia\n" +
+		"  - url: https://github.com/isacikgoz/tldr.git\n" +
+		"    path: /tmp/tldr\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	m, err := loadManifest(path)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		expected string
+	}{
+		{filepath.Join(dir, "gitbatch")},
+		{filepath.Join(dir, "nested", "gia")},
+		{"/tmp/tldr"},
+	}
+	if len(m.Repositories) != len(tests) {
+		t.Fatalf("Test Failed. %d repositories loaded, expected: %d", len(m.Repositories), len(tests))
+	}
+	for i, test := range tests {
+		if output := m.Repositories[i].Path; output != test.expected {
+			t.Errorf("Test Failed. output: %s, expected: %s", output, test.expected)
+		}
+	}
+	if e := m.Repositories[0]; e.Branch != "master" || len(e.Groups) != 1 || e.Groups[0] != "tools" {
+		t.Errorf("Test Failed. branch and groups are not loaded: %v", e)
+	}
+	if m, err := loadManifest(filepath.Join(dir, "missing.yml")); m != nil || err != nil {
+		t.Errorf("Test Failed. missing manifest should be ignored")
+	}
+}
+
+func TestSyncManifest(t *testing.T) {
+	dir, err := testutil.LocalRemote()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	remote := filepath.Join(dir, "remote.git")
+	m := &manifest{
+		Repositories: []*manifestEntry{
+			{URL: remote, Path: filepath.Join(dir, "local")},
+			{URL: remote, Path: filepath.Join(dir, "clones", "first")},
+			{URL: remote, Path: filepath.Join(dir, "clones", "second")},
+			{URL: filepath.Join(dir, "missing.git"), Path: filepath.Join(dir, "clones", "missing")},
+		},
+	}
+	var buf bytes.Buffer
+	paths := syncManifest(&buf, m, &Config{})
+	if len(paths) != 3 {
+		t.Errorf("Test Failed. %d repositories are ready, expected: 3", len(paths))
+	}
+	if output := buf.String(); strings.Count(output, "cloned") != 2 || !strings.Contains(output, "could not clone") {
+		t.Errorf("Test Failed. output: %s", output)
+	}
+}
+
+func TestRepositoryName(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"git@github.com:isacikgoz/gitbatch.git", "gitbatch"},
+		{"https://github.com/isacikgoz/gitbatch.git", "gitbatch"},
+		{"https://github.com/isacikgoz/gitbatch/", "gitbatch"},
+		{"git@github.com:gitbatch", "gitbatch"},
+		{"/srv/git/gitbatch.git", "gitbatch"},
+	}
+	for _, test := range tests {
+		if output := repositoryName(test.input); output != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, output, test.expected)
+		}
+	}
+}
+
+func TestAppendDirectories(t *testing.T) {
+	var tests = []struct {
+		inp1     []string
+		inp2     []string
+		expected int
+	}{
+		{[]string{"/a", "/b"}, []string{"/b/", "/c"}, 3},
+		{[]string{}, []string{"/a", "/a"}, 1},
+	}
+	for _, test := range tests {
+		if output := appendDirectories(test.inp1, test.inp2...); len(output) != test.expected {
+			t.Errorf("Test Failed. output: %v, expected %d directories", output, test.expected)
+		}
+	}
+}
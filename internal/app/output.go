@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+)
+
+// output formats of the quick mode
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+// result is the outcome of an operation on a single repository
+type result struct {
+	Type      string `json:"type"`
+	Path      string `json:"path"`
+	Branch    string `json:"branch,omitempty"`
+	Operation string `json:"operation"`
+	Before    string `json:"before,omitempty"`
+	After     string `json:"after,omitempty"`
+	Ahead     *int   `json:"ahead,omitempty"`
+	Behind    *int   `json:"behind,omitempty"`
+	Duration  int64  `json:"duration_ms"`
+	Error     string `json:"error,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// summary is the overall outcome of the quick mode
+type summary struct {
+	Type      string `json:"type"`
+	Operation string `json:"operation"`
+	Total     int    `json:"total"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Duration  int64  `json:"duration_ms"`
+}
+
+// reporter writes the results in the requested format. Text and ndjson
+// records are written as soon as they are reported, json output is written as
+// a single document on summary
+type reporter struct {
+	w       io.Writer
+	format  string
+	results []*result
+	mutex   *sync.Mutex
+}
+
+func newReporter(w io.Writer, format string) (*reporter, error) {
+	switch format {
+	case "":
+		format = outputText
+	case outputText, outputJSON, outputNDJSON:
+	default:
+		return nil, fmt.Errorf("unrecognized output format: %s", format)
+	}
+	return &reporter{
+		w:       w,
+		format:  format,
+		results: make([]*result, 0),
+		mutex:   &sync.Mutex{},
+	}, nil
+}
+
+// report writes or collects the result of a repository, it is safe to call it
+// from multiple goroutines
+func (rp *reporter) report(res *result) error {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	res.Type = "repository"
+	rp.results = append(rp.results, res)
+	switch rp.format {
+	case outputNDJSON:
+		return json.NewEncoder(rp.w).Encode(res)
+	case outputText:
+		if len(res.Error) > 0 {
+			_, err := fmt.Fprintf(rp.w, "could not perform %s on %s: %s\n", res.Operation, res.Path, res.Message)
+			return err
+		}
+		_, err := fmt.Fprintf(rp.w, "%s: successful\n", res.Path)
+		return err
+	}
+	return nil
+}
+
+// summarize writes the summary, for json output the collected results are
+// written along with it
+func (rp *reporter) summarize(s *summary) error {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	s.Type = "summary"
+	switch rp.format {
+	case outputNDJSON:
+		return json.NewEncoder(rp.w).Encode(s)
+	case outputJSON:
+		enc := json.NewEncoder(rp.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Repositories []*result `json:"repositories"`
+			Summary      *summary  `json:"summary"`
+		}{rp.results, s})
+	}
+	elapsed := time.Duration(s.Duration) * time.Millisecond
+	if s.Failed > 0 {
+		_, err := fmt.Fprintf(rp.w, "%d repositories finished in: %s, %d failed\n", s.Total, elapsed, s.Failed)
+		return err
+	}
+	_, err := fmt.Fprintf(rp.w, "%d repositories finished in: %s\n", s.Total, elapsed)
+	return err
+}
+
+// classify maps the error to one of the known errors so that it can be handled
+// by the scripts consuming the output
+func classify(err error) string {
+	var e *gerr.Error
+	if errors.As(err, &e) {
+		return e.Category.Error()
+	}
+	if e, ok := err.(gerr.GitError); ok {
+		return e.Error()
+	}
+	switch err {
+	case context.Canceled:
+		return "cancelled"
+	case context.DeadlineExceeded:
+		return "timed out"
+	}
+	return gerr.ErrUnclassified.Error()
+}
+
+// gitOutput returns the raw output of the failed git command, empty if the
+// error is not caused by a git command
+func gitOutput(err error) string {
+	var e *gerr.Error
+	if errors.As(err, &e) {
+		return e.Output
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+)
+
+func TestNewReporter(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+		fail     bool
+	}{
+		{"", outputText, false},
+		{outputJSON, outputJSON, false},
+		{outputNDJSON, outputNDJSON, false},
+		{"xml", "", true},
+	}
+	for _, test := range tests {
+		rp, err := newReporter(&bytes.Buffer{}, test.input)
+		if (err != nil) != test.fail {
+			t.Errorf("Test Failed. %s inputted, error: %v", test.input, err)
+		}
+		if err == nil && rp.format != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, rp.format, test.expected)
+		}
+	}
+}
+
+func TestReportNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	rp, err := newReporter(&buf, outputNDJSON)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	rp.report(&result{Path: "a", Operation: "fetch"})
+	rp.report(&result{Path: "b", Operation: "fetch", Error: gerr.ErrRemoteNotFound.Error()})
+	rp.summarize(&summary{Operation: "fetch", Total: 2, Succeeded: 1, Failed: 1})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Test Failed. %d lines written, expected: 3", len(lines))
+	}
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{lines[0], "repository"},
+		{lines[1], "repository"},
+		{lines[2], "summary"},
+	}
+	for _, test := range tests {
+		var record struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(test.input), &record); err != nil || record.Type != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, record.Type, test.expected)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	var tests = []struct {
+		input    error
+		expected string
+	}{
+		{gerr.ErrAuthenticationRequired, "authentication required"},
+		{&gerr.Error{Category: gerr.ErrUnmergedFiles, Output: "error: unmerged"}, gerr.ErrUnmergedFiles.Error()},
+		{context.DeadlineExceeded, "timed out"},
+		{context.Canceled, "cancelled"},
+		{errors.New("something"), "unclassified error"},
+	}
+	for _, test := range tests {
+		if output := classify(test.input); output != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, output, test.expected)
+		}
+	}
+}
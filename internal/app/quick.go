@@ -1,49 +1,203 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"runtime"
 	"sync"
 	"time"
 
 	"github.com/isacikgoz/gitbatch/internal/command"
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/job"
+	"golang.org/x/sync/semaphore"
 )
 
-func quick(directories []string, mode string) error {
-	var wg sync.WaitGroup
+// quick runs the job of the given mode on the repositories without the gui.
+// The jobs are started from the same queue that gui uses.
+func quick(w io.Writer, directories []string, c *Config) error {
+	rp, err := newReporter(w, c.Output)
+	if err != nil {
+		return err
+	}
+	jobType, err := job.ParseType(c.Mode)
+	if err != nil {
+		return err
+	}
+	if jobType == job.CheckoutJob && len(c.Branch) == 0 {
+		return fmt.Errorf("a target branch is required for checkout")
+	}
 	start := time.Now()
-	for _, dir := range directories {
+	results, repositories := loadRepositories(directories, c.Mode, c.LoadConcurrency)
+
+	q := job.CreateJobQueue()
+	q.SetLimits(job.Limits{
+		Workers: c.Concurrency,
+		PerHost: c.HostConcurrency,
+	})
+	q.SetHistory(c.history())
+	jobs := make(map[*git.Repository]*job.Job)
+	for i, r := range repositories {
+		if r == nil {
+			continue
+		}
+		j := &job.Job{
+			JobType:    jobType,
+			Repository: r,
+			Options:    jobOptions(jobType, r, c),
+			Timeout:    c.Timeout,
+			Retry:      c.retryPolicy(),
+		}
+		if err := q.AddJob(j); err != nil {
+			results[i].Error, results[i].Message = classify(err), err.Error()
+			continue
+		}
+		jobs[r] = j
+		track(r, results[i])
+	}
+	fails := q.StartJobsAsync(context.Background())
+
+	failed := 0
+	for i, r := range repositories {
+		res := results[i]
+		if j, ok := jobs[r]; ok {
+			if err, ok := fails[j]; ok {
+				res.Error, res.Message = classify(err), r.State.Message
+				res.Output = gitOutput(err)
+			} else if r.WorkStatus() == git.Fail {
+				res.Error, res.Message = classify(nil), r.State.Message
+			} else {
+				res.After = head(r)
+				res.Ahead = count(r.State.Branch, r.State.Branch.Pushables)
+				res.Behind = count(r.State.Branch, r.State.Branch.Pullables)
+			}
+		}
+		if len(res.Error) > 0 {
+			failed++
+		}
+		rp.report(res)
+	}
+	if err := rp.summarize(&summary{
+		Operation: c.Mode,
+		Total:     len(directories),
+		Succeeded: len(directories) - failed,
+		Failed:    failed,
+		Duration:  time.Since(start).Milliseconds(),
+	}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not perform %s on %d of %d repositories", c.Mode, failed, len(directories))
+	}
+	return nil
+}
+
+// jobOptions returns the options of the job on the repository, nil means the
+// defaults of the job. Each job gets its own options since they are modified
+// while the job runs.
+func jobOptions(jobType job.Type, r *git.Repository, c *Config) interface{} {
+	switch jobType {
+	case job.CheckoutJob:
+		return &command.CheckoutOptions{
+			TargetRef:      c.Branch,
+			CreateIfAbsent: true,
+		}
+	case job.PullJob:
+		if c.Rebase && r.State.Remote != nil {
+			return &command.PullOptions{
+				RemoteName:  r.State.Remote.Name,
+				Rebase:      true,
+				AutoStash:   c.AutoStash,
+				CommandMode: command.ModeLegacy,
+			}
+		} else if c.FFOnly && r.State.Remote != nil {
+			return &command.PullOptions{
+				RemoteName:  r.State.Remote.Name,
+				FFOnly:      true,
+				CommandMode: command.ModeNative,
+			}
+		}
+	case job.MergeJob:
+		if c.FFOnly {
+			return &command.MergeOptions{
+				FFOnly: true,
+			}
+		}
+	case job.RebaseJob:
+		return &command.RebaseOptions{
+			AutoStash: c.AutoStash,
+		}
+	}
+	return nil
+}
+
+// loadRepositories initializes the repositories at given directories with at most
+// maxWorkers at a time. The order of the directories is kept, a repository is
+// nil if it couldn't be loaded and the reason is in its result
+func loadRepositories(directories []string, mode string, maxWorkers int) ([]*result, []*git.Repository) {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0)
+	}
+	var (
+		sem          = semaphore.NewWeighted(int64(maxWorkers))
+		wg           sync.WaitGroup
+		results      = make([]*result, len(directories))
+		repositories = make([]*git.Repository, len(directories))
+	)
+	for i, dir := range directories {
 		wg.Add(1)
-		go func(d string, mode string) {
+		go func(i int, d string) {
 			defer wg.Done()
-			if err := operate(d, mode); err != nil {
-				fmt.Printf("could not perform %s on %s: %s", mode, d, err)
+			sem.Acquire(context.Background(), 1)
+			defer sem.Release(1)
+			res := &result{
+				Path:      d,
+				Operation: mode,
 			}
-			fmt.Printf("%s: successful\n", d)
-		}(dir, mode)
+			results[i] = res
+			r, err := git.InitializeRepo(d)
+			if err != nil {
+				res.Error, res.Message = classify(err), err.Error()
+				return
+			}
+			res.Branch = r.State.Branch.Name
+			res.Before = head(r)
+			repositories[i] = r
+		}(i, dir)
 	}
 	wg.Wait()
-	elapsed := time.Since(start)
-	fmt.Printf("%d repositories finished in: %s\n", len(directories), elapsed)
-	return nil
+	return results, repositories
+}
+
+// track listens the work status of the repository to measure the duration of
+// its job
+func track(r *git.Repository, res *result) {
+	var start time.Time
+	r.On(git.RepositoryUpdated, func(event *git.RepositoryEvent) error {
+		if r.WorkStatus() == git.Working {
+			start = time.Now()
+		} else if !start.IsZero() {
+			res.Duration = time.Since(start).Milliseconds()
+		}
+		return nil
+	})
 }
 
-func operate(directory, mode string) error {
-	r, err := git.FastInitializeRepo(directory)
+// head returns the hash of the current HEAD, empty if it cannot be resolved
+func head(r *git.Repository) string {
+	ref, err := r.Repo.Head()
 	if err != nil {
-		return err
+		return ""
 	}
-	switch mode {
-	case "fetch":
-		return command.Fetch(r, &command.FetchOptions{
-			RemoteName: "origin",
-			Progress:   true,
-		})
-	case "pull":
-		return command.Pull(r, &command.PullOptions{
-			RemoteName: "origin",
-			Progress:   true,
-		})
+	return ref.Hash().String()
+}
+
+// count returns the pushables/pullables, nil means it is unknown since the
+// branch has no upstream or its commits couldn't be counted
+func count(b *git.Branch, n int) *int {
+	if !b.Counted() {
+		return nil
 	}
-	return nil
+	return &n
 }
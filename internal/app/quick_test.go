@@ -1,7 +1,14 @@
 package app
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/testutil"
 )
 
 func TestQuick(t *testing.T) {
@@ -23,6 +30,57 @@ func TestQuick(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		quick(test.inp1, test.inp2)
+		quick(ioutil.Discard, test.inp1, &Config{Mode: test.inp2})
+	}
+}
+
+func TestQuickFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "non-repo")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{outputText, "1 failed"},
+		{outputNDJSON, `"failed":1`},
+		{outputJSON, `"failed": 1`},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := quick(&buf, []string{dir}, &Config{Mode: "fetch", Output: test.input}); err == nil {
+			t.Errorf("Test Failed. %s inputted, no error returned", test.input)
+		}
+		if output := buf.String(); !strings.Contains(output, test.expected) || strings.Contains(output, "successful") {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, output, test.expected)
+		}
+	}
+}
+
+func TestQuickJobTypes(t *testing.T) {
+	dir, err := testutil.LocalRemote()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	local := filepath.Join(dir, "local")
+	var tests = []struct {
+		input *Config
+		fail  bool
+	}{
+		{&Config{Mode: "fetch"}, false},
+		{&Config{Mode: "pull"}, false},
+		{&Config{Mode: "merge"}, false},
+		{&Config{Mode: "push"}, false},
+		{&Config{Mode: "checkout", Branch: "feature"}, false},
+		{&Config{Mode: "checkout"}, true},
+		{&Config{Mode: "unknown"}, true},
+	}
+	for _, test := range tests {
+		if err := quick(ioutil.Discard, []string{local}, test.input); (err != nil) != test.fail {
+			t.Errorf("Test Failed. %s inputted, error: %v", test.input.Mode, err)
+		}
 	}
 }
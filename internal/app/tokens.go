@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/spf13/viper"
+)
+
+// tokenConfig is the access token of a host in the configuration file e.g.
+//
+//	tokens:
+//	  gitlab.example.com:
+//	    env: GITLAB_TOKEN
+//	    type: bearer
+type tokenConfig struct {
+	// User is sent along with the token in basic auth, some servers require
+	// a specific one
+	User string `mapstructure:"user"`
+	// Token is the token itself, it is better to keep it in an environment
+	// variable and set Env instead
+	Token string `mapstructure:"token"`
+	// Env is the environment variable that holds the token
+	Env string `mapstructure:"env"`
+	// Type is either basic, the default, or bearer
+	Type string `mapstructure:"type"`
+}
+
+// loadTokens reads the token credentials of the hosts from the configuration
+func loadTokens() (map[string]*git.Credentials, error) {
+	configs := make(map[string]tokenConfig)
+	if err := viper.UnmarshalKey(tokensKey, &configs); err != nil {
+		return nil, err
+	}
+	return parseTokens(configs)
+}
+
+// parseTokens resolves the tokens of the hosts, the hosts whose environment
+// variable is empty are skipped
+func parseTokens(configs map[string]tokenConfig) (map[string]*git.Credentials, error) {
+	tokens := make(map[string]*git.Credentials)
+	for host, tc := range configs {
+		c := &git.Credentials{User: tc.User, Token: tc.Token}
+		if len(tc.Env) > 0 {
+			c.Token = os.Getenv(tc.Env)
+		}
+		switch tc.Type {
+		case "", "basic":
+		case "bearer":
+			c.Bearer = true
+		default:
+			return nil, fmt.Errorf("unknown token type %q for %s", tc.Type, host)
+		}
+		if len(c.Token) == 0 {
+			continue
+		}
+		tokens[host] = c
+	}
+	return tokens, nil
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseTokens(t *testing.T) {
+	defer os.Unsetenv("GITBATCH_TEST_TOKEN")
+	os.Setenv("GITBATCH_TEST_TOKEN", "from-env")
+	output, err := parseTokens(map[string]tokenConfig{
+		"github.example.com": {User: "isacikgoz", Token: "plain"},
+		"gitlab.example.com": {Env: "GITBATCH_TEST_TOKEN", Type: "bearer"},
+		"empty.example.com":  {Env: "GITBATCH_UNSET_TOKEN"},
+	})
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if c := output["github.example.com"]; c == nil || c.Token != "plain" || c.User != "isacikgoz" || c.Bearer {
+		t.Errorf("Test Failed. output: %v", c)
+	}
+	if c := output["gitlab.example.com"]; c == nil || c.Token != "from-env" || !c.Bearer {
+		t.Errorf("Test Failed. output: %v", c)
+	}
+	if c, ok := output["empty.example.com"]; ok {
+		t.Errorf("Test Failed. output: %v, expected: nil", c)
+	}
+	if _, err := parseTokens(map[string]tokenConfig{"example.com": {Token: "t", Type: "digest"}}); err == nil {
+		t.Errorf("Test Failed. unknown token type is accepted")
+	}
+}
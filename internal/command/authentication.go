@@ -0,0 +1,110 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"golang.org/x/crypto/ssh"
+)
+
+// authMethod returns the authentication of the native commands for the remote.
+// HTTP remotes use the given credentials or the token of the host if there is
+// one, tokens are sent as basic auth or bearer header. SSH remotes use the
+// private key of the credentials, the ssh-agent if it is running or the default
+// private key of the user, in that order. Host keys of SSH remotes are verified
+// against the known_hosts files. A nil method lets go-git decide.
+func authMethod(r *git.Remote, c *git.Credentials) (transport.AuthMethod, error) {
+	protocol, err := git.AuthProtocol(r)
+	if err != nil {
+		return nil, err
+	}
+	switch protocol {
+	case git.AuthProtocolHTTP, git.AuthProtocolHTTPS:
+		if c == nil {
+			if host, err := git.RemoteHost(r); err == nil {
+				c = git.Token(host)
+			}
+		}
+		if c == nil {
+			return nil, nil
+		}
+		return httpAuthMethod(c), nil
+	case git.AuthProtocolSSH:
+		return sshAuthMethod(r, c)
+	}
+	if c != nil {
+		return nil, gerr.ErrInvalidAuthMethod
+	}
+	return nil, nil
+}
+
+func httpAuthMethod(c *git.Credentials) transport.AuthMethod {
+	if len(c.Token) == 0 {
+		return &http.BasicAuth{
+			Username: c.User,
+			Password: c.Password,
+		}
+	}
+	if c.Bearer {
+		return &http.TokenAuth{Token: c.Token}
+	}
+	// the user name is not checked by the most of the servers but it can't
+	// be empty
+	user := c.User
+	if len(user) == 0 {
+		user = "oauth2"
+	}
+	return &http.BasicAuth{
+		Username: user,
+		Password: c.Token,
+	}
+}
+
+func sshAuthMethod(r *git.Remote, c *git.Credentials) (transport.AuthMethod, error) {
+	user := "git"
+	if ep, err := transport.NewEndpoint(r.URL[0]); err == nil && len(ep.User) > 0 {
+		user = ep.User
+	}
+	var key, passphrase string
+	if c != nil {
+		key, passphrase = c.PrivateKey, c.Passphrase
+	}
+	if len(key) == 0 {
+		if len(os.Getenv("SSH_AUTH_SOCK")) > 0 {
+			return gitssh.NewSSHAgentAuth(user)
+		}
+		key = git.DefaultPrivateKey()
+	}
+	if len(key) == 0 {
+		// go-git fails without an agent and the git is tried instead
+		return nil, nil
+	}
+	signer, err := privateKeySigner(key, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	// nil HostKeyCallback means the default known_hosts files are used
+	return &gitssh.PublicKeys{User: user, Signer: signer}, nil
+}
+
+// privateKeySigner reads the private key, the passphrase is required if the
+// key is protected
+func privateKeySigner(path, passphrase string) (ssh.Signer, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		if len(passphrase) == 0 {
+			return nil, gerr.ErrAuthenticationRequired
+		}
+		return ssh.ParsePrivateKeyWithPassphrase(pem, []byte(passphrase))
+	}
+	return signer, err
+}
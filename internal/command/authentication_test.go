@@ -0,0 +1,76 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+func TestAuthMethod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh-keys")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	plain := filepath.Join(dir, "plain")
+	protected := filepath.Join(dir, "protected")
+	for key, passphrase := range map[string]string{plain: "", protected: "secret"} {
+		if out, err := Run(dir, "ssh-keygen", []string{"-q", "-t", "ed25519", "-N", passphrase, "-f", key}); err != nil {
+			t.Skipf("ssh-keygen is not available: %s", out)
+		}
+	}
+	var (
+		httpRemote  = &git.Remote{URL: []string{"https://github.com/isacikgoz/gitbatch.git"}}
+		sshRemote   = &git.Remote{URL: []string{"git@github.com:isacikgoz/gitbatch.git"}}
+		gitRemote   = &git.Remote{URL: []string{"git://github.com/isacikgoz/gitbatch.git"}}
+		tokenRemote = &git.Remote{URL: []string{"https://gitlab.example.com/isacikgoz/gitbatch.git"}}
+	)
+	git.SetToken("gitlab.example.com", &git.Credentials{Token: "token", Bearer: true})
+	var tests = []struct {
+		remote   *git.Remote
+		input    *git.Credentials
+		expected interface{}
+		err      error
+	}{
+		{httpRemote, nil, nil, nil},
+		{httpRemote, &git.Credentials{User: "user", Password: "pass"}, &http.BasicAuth{}, nil},
+		{httpRemote, &git.Credentials{Token: "token"}, &http.BasicAuth{}, nil},
+		{httpRemote, &git.Credentials{Token: "token", Bearer: true}, &http.TokenAuth{}, nil},
+		{tokenRemote, nil, &http.TokenAuth{}, nil},
+		{sshRemote, &git.Credentials{PrivateKey: plain}, &gitssh.PublicKeys{}, nil},
+		{sshRemote, &git.Credentials{PrivateKey: protected}, nil, gerr.ErrAuthenticationRequired},
+		{sshRemote, &git.Credentials{PrivateKey: protected, Passphrase: "secret"}, &gitssh.PublicKeys{}, nil},
+		{gitRemote, &git.Credentials{User: "user", Password: "pass"}, nil, gerr.ErrInvalidAuthMethod},
+	}
+	for _, test := range tests {
+		output, err := authMethod(test.remote, test.input)
+		if err != test.err {
+			t.Errorf("Test Failed. %s inputted, error: %v, expected: %v", test.remote.URL[0], err, test.err)
+			continue
+		}
+		switch test.expected.(type) {
+		case *http.BasicAuth:
+			if _, ok := output.(*http.BasicAuth); !ok {
+				t.Errorf("Test Failed. output: %v, expected basic auth", output)
+			}
+		case *http.TokenAuth:
+			if _, ok := output.(*http.TokenAuth); !ok {
+				t.Errorf("Test Failed. output: %v, expected token auth", output)
+			}
+		case *gitssh.PublicKeys:
+			if pk, ok := output.(*gitssh.PublicKeys); !ok || pk.User != "git" {
+				t.Errorf("Test Failed. output: %v, expected public keys of git user", output)
+			}
+		default:
+			if output != nil {
+				t.Errorf("Test Failed. output: %v, expected: nil", output)
+			}
+		}
+	}
+}
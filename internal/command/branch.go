@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// DeleteBranchOptions defines the rules of a branch deletion
+type DeleteBranchOptions struct {
+	// Branches are the names of the local branches to be deleted
+	Branches []string
+	// Force deletes the branches even if they are not merged into their
+	// upstreams or HEAD, the caller is expected to check them beforehand
+	Force bool
+}
+
+// DeleteBranches deletes the local branches along with their configurations
+// in a single command
+func DeleteBranches(ctx context.Context, r *git.Repository, options *DeleteBranchOptions) error {
+	if len(options.Branches) == 0 {
+		return nil
+	}
+	args := []string{"branch", "-d"}
+	if options.Force {
+		args[1] = "-D"
+	}
+	args = append(args, options.Branches...)
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestDeleteBranches(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	for _, args := range [][]string{
+		{"checkout", "-q", "-b", "wip"},
+		testCommitArgs("work in progress"),
+		{"checkout", "-q", "master"},
+	} {
+		if out, err := Run(r.AbsPath, "git", args); err != nil {
+			t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+		}
+	}
+	var tests = []struct {
+		input    *DeleteBranchOptions
+		expected error
+	}{
+		{&DeleteBranchOptions{Branches: []string{"wip"}}, gerr.ErrBranchNotMerged},
+		{&DeleteBranchOptions{Branches: []string{"wip"}, Force: true}, nil},
+	}
+	for _, test := range tests {
+		if err := DeleteBranches(context.Background(), r, test.input); !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. error: %v, expected: %v", err, test.expected)
+		}
+	}
+	if out, _ := Run(r.AbsPath, "git", []string{"branch", "--list", "wip"}); len(out) > 0 {
+		t.Errorf("Test Failed. branch is not deleted")
+	}
+}
@@ -1,7 +1,7 @@
 package command
 
 import (
-	"os/exec"
+	"context"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
 )
@@ -14,7 +14,11 @@ type CheckoutOptions struct {
 }
 
 // Checkout is a wrapper function for "git checkout" command.
-func Checkout(r *git.Repository, o *CheckoutOptions) error {
+func Checkout(ctx context.Context, r *git.Repository, o *CheckoutOptions) error {
+	// go-git checkout cannot be interrupted, so at least don't start it
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	var branch *git.Branch
 	for _, b := range r.Branches {
 		if b.Name == o.TargetRef {
@@ -33,10 +37,10 @@ func Checkout(r *git.Repository, o *CheckoutOptions) error {
 		}
 	} else if o.CreateIfAbsent {
 		args := []string{"checkout", "-b", o.TargetRef}
-		cmd := exec.Command("git", args...)
-		cmd.Dir = r.AbsPath
-		_, err := cmd.CombinedOutput()
-		if err != nil {
+		_, err := RunContext(ctx, r.AbsPath, "git", args)
+		if err != nil && ctx.Err() != nil {
+			return err
+		} else if err != nil {
 			r.SetWorkStatus(git.Fail)
 			msg = err.Error()
 		} else {
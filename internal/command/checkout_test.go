@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"testing"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
@@ -30,7 +31,7 @@ func TestCheckout(t *testing.T) {
 		{r, opts2},
 	}
 	for _, test := range tests {
-		if err := Checkout(test.inp1, test.inp2); err != nil {
+		if err := Checkout(context.Background(), test.inp1, test.inp2); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// CloneOptions defines the rules for clone operation
+type CloneOptions struct {
+	// URL of the repository to be cloned
+	URL string
+	// Path is the directory that the repository is cloned into
+	Path string
+	// Branch to be checked out, remote's default branch if empty
+	Branch string
+	// Credentials holds the user and password information
+	Credentials *git.Credentials
+	// Process logs the output to stdout
+	Progress bool
+	// Mode is the command mode
+	CommandMode Mode
+}
+
+// Clone clones a repository into a newly created directory. Unlike the other
+// commands, there is no repository to operate on yet.
+func Clone(ctx context.Context, o *CloneOptions) (err error) {
+	if err := os.MkdirAll(filepath.Dir(o.Path), 0755); err != nil {
+		return err
+	}
+	switch o.CommandMode {
+	case ModeLegacy:
+		err = cloneWithGit(ctx, o)
+		return err
+	case ModeNative:
+		err = cloneWithGoGit(ctx, o)
+		return err
+	}
+	return nil
+}
+
+// cloneWithGit is simply a bare git clone <url> <path> command
+func cloneWithGit(ctx context.Context, options *CloneOptions) (err error) {
+	args := make([]string, 0)
+	args = append(args, "clone")
+	if len(options.Branch) > 0 {
+		args = append(args, "--branch", options.Branch)
+	}
+	args = append(args, options.URL, options.Path)
+	if out, err := RunContext(ctx, filepath.Dir(options.Path), "git", args); err != nil {
+		return gerr.NewGitError(filepath.Dir(options.Path), args, out, err)
+	}
+	return nil
+}
+
+// cloneWithGoGit is the primary clone method, it falls back to git if go-git
+// can't handle the remote
+func cloneWithGoGit(ctx context.Context, options *CloneOptions) (err error) {
+	opt := &gogit.CloneOptions{
+		URL: options.URL,
+	}
+	if len(options.Branch) > 0 {
+		opt.ReferenceName = plumbing.NewBranchReferenceName(options.Branch)
+	}
+	// the credentials are added to the git.CloneOptions, if there is any
+	if opt.Auth, err = authMethod(&git.Remote{URL: []string{options.URL}}, options.Credentials); err != nil {
+		return err
+	}
+	if options.Progress {
+		opt.Progress = os.Stdout
+	}
+	// go-git removes the directory if clone fails, so that git can try again
+	if _, err := gogit.PlainCloneContext(ctx, options.Path, false, opt); err != nil {
+		if ctx.Err() != nil {
+			// the operation is cancelled or timed out, don't try anything else
+			return ctx.Err()
+		} else if strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
+			// The env variable SSH_AUTH_SOCK is not defined, maybe git can handle this
+			return cloneWithGit(ctx, options)
+		} else if err == transport.ErrAuthenticationRequired {
+			return gerr.ErrAuthenticationRequired
+		} else if err == gogit.ErrRepositoryAlreadyExists {
+			return err
+		} else {
+			return cloneWithGit(ctx, options)
+		}
+	}
+	return nil
+}
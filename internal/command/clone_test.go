@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestClone(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	root := filepath.Dir(r.AbsPath)
+	remote := filepath.Join(root, "remote.git")
+	var tests = []struct {
+		input *CloneOptions
+	}{
+		{&CloneOptions{URL: remote, Path: filepath.Join(root, "legacy"), CommandMode: ModeLegacy}},
+		{&CloneOptions{URL: remote, Path: filepath.Join(root, "native"), CommandMode: ModeNative}},
+		{&CloneOptions{URL: remote, Path: filepath.Join(root, "nested", "branch"), Branch: r.State.Branch.Name, CommandMode: ModeNative}},
+	}
+	for _, test := range tests {
+		if err := Clone(context.Background(), test.input); err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+			continue
+		}
+		if _, err := git.InitializeRepo(test.input.Path); err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+		}
+	}
+}
+
+func TestCloneExisting(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	remote := filepath.Join(filepath.Dir(r.AbsPath), "remote.git")
+	var tests = []struct {
+		input *CloneOptions
+	}{
+		{&CloneOptions{URL: remote, Path: r.AbsPath, CommandMode: ModeLegacy}},
+		{&CloneOptions{URL: remote, Path: r.AbsPath, CommandMode: ModeNative}},
+	}
+	for _, test := range tests {
+		if err := Clone(context.Background(), test.input); err == nil {
+			t.Errorf("Test Failed. cloned into an existing repository")
+		}
+	}
+	if _, err := git.InitializeRepo(r.AbsPath); err != nil {
+		t.Errorf("Test Failed. existing repository is broken: %s", err.Error())
+	}
+}
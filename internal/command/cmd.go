@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"log"
 	"os/exec"
 	"strings"
@@ -22,11 +23,20 @@ const (
 // returns error it also encapsulates it as a golang.error which is a return code
 // of the command except zero
 func Run(d string, c string, args []string) (string, error) {
-	cmd := exec.Command(c, args...)
+	return RunContext(context.Background(), d, c, args)
+}
+
+// RunContext is same as Run but the command is killed if the context is done
+// before the command completes. In that case the context's error is returned.
+func RunContext(ctx context.Context, d string, c string, args []string) (string, error) {
+	cmd := exec.CommandContext(ctx, c, args...)
 	if d != "" {
 		cmd.Dir = d
 	}
 	output, err := cmd.CombinedOutput()
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
 	return trimTrailingNewline(string(output)), err
 }
 
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -33,6 +35,18 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRunContext(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Test Failed.")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RunContext(ctx, wd, "git", []string{"status"}); err != context.Canceled {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, context.Canceled)
+	}
+}
+
 func TestReturn(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -82,6 +96,18 @@ func testRepo() (*git.Repository, error) {
 	return git.InitializeRepo(testRepoDir)
 }
 
+// testCommit adds an empty commit to the repository
+func testCommit(r *git.Repository, msg string) error {
+	if out, err := Run(r.AbsPath, "git", testCommitArgs(msg)); err != nil {
+		return fmt.Errorf("%s: %s", err, out)
+	}
+	return r.Refresh()
+}
+
+func testCommitArgs(msg string) []string {
+	return []string{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", msg}
+}
+
 func testFile(name string) (*git.File, error) {
 	_, err := os.Create(testRepoDir + string(os.PathSeparator) + name)
 	if err != nil {
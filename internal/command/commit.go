@@ -46,7 +46,7 @@ func commitWithGit(r *git.Repository, opt *CommitOptions) (err error) {
 	}
 	if out, err := Run(r.AbsPath, "git", args); err != nil {
 		r.Refresh()
-		return giterr.ParseGitError(out, err)
+		return giterr.NewGitError(r.AbsPath, args, out, err)
 	}
 	// till this step everything should be ok
 	return r.Refresh()
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// ConflictSide is the version of a conflicted file to be kept
+type ConflictSide string
+
+const (
+	// Ours is the version of the current branch
+	Ours ConflictSide = "ours"
+	// Theirs is the version of the branch that is being merged, or the
+	// upstream commit that is being applied in case of a rebase
+	Theirs ConflictSide = "theirs"
+)
+
+// Unmerged returns the files that have conflicts waiting to be resolved
+func Unmerged(r *git.Repository) ([]*git.File, error) {
+	files, err := Status(r)
+	if err != nil {
+		return nil, err
+	}
+	unmerged := make([]*git.File, 0)
+	for _, f := range files {
+		if isUnmerged(f) {
+			unmerged = append(unmerged, f)
+		}
+	}
+	return unmerged, nil
+}
+
+// the unmerged files are either updated on one side or both added/deleted,
+// see the short format of git status
+func isUnmerged(f *git.File) bool {
+	if f.X == git.StatusUpdated || f.Y == git.StatusUpdated {
+		return true
+	}
+	return f.X == f.Y && (f.X == git.StatusAdded || f.X == git.StatusDeleted)
+}
+
+// CheckoutConflict replaces the conflicted file with the given side of it, the
+// file still has to be added to be marked as resolved
+func CheckoutConflict(r *git.Repository, f *git.File, side ConflictSide) error {
+	args := []string{"checkout", "--" + string(side), "--", f.Name}
+	if out, err := Run(r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	return nil
+}
+
+// DiffConflict returns the combined diff of the conflicted file against both
+// sides, only the hunks that couldn't be merged are left in it
+func DiffConflict(f *git.File) (string, error) {
+	args := []string{"diff", "--", f.Name}
+	return Run(strings.TrimSuffix(f.AbsPath, f.Name), "git", args)
+}
+
+// Abort stops the merge or rebase that is waiting for the conflicts to be
+// resolved
+func Abort(ctx context.Context, r *git.Repository) error {
+	if r.Rebasing() {
+		return RebaseAbort(ctx, r)
+	}
+	return MergeAbort(ctx, r)
+}
+
+// Continue concludes the merge or continues the rebase once the conflicts are
+// resolved
+func Continue(ctx context.Context, r *git.Repository) error {
+	if r.Rebasing() {
+		return RebaseContinue(ctx, r)
+	}
+	return MergeContinue(ctx, r)
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestResolveConflict(t *testing.T) {
+	var tests = []struct {
+		side     ConflictSide
+		expected string
+	}{
+		{Ours, "local"},
+		{Theirs, "remote"},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testConflictedRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		files, err := Unmerged(r)
+		if err != nil || len(files) != 1 || files[0].Name != "conflict.txt" {
+			t.Fatalf("Test Failed. unmerged files: %v, error: %v", files, err)
+		}
+		if out, err := DiffConflict(files[0]); err != nil || len(out) == 0 {
+			t.Errorf("Test Failed. conflict diff: %q, error: %v", out, err)
+		}
+		if err := CheckoutConflict(r, files[0], test.side); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := Add(r, files[0], &AddOptions{}); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if files, _ := Unmerged(r); len(files) != 0 {
+			t.Errorf("Test Failed. file is not resolved")
+		}
+		if err := Continue(context.Background(), r); err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+		}
+		if r.Merging() {
+			t.Errorf("Test Failed. merge is not concluded")
+		}
+		if out, _ := ioutil.ReadFile(filepath.Join(r.AbsPath, "conflict.txt")); string(out) != test.expected {
+			t.Errorf("Test Failed. output: %q, expected: %q", out, test.expected)
+		}
+		cleanup()
+	}
+}
+
+func TestAbortConflict(t *testing.T) {
+	r, cleanup, err := testConflictedRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	if err := Abort(context.Background(), r); err != nil {
+		t.Errorf("Test Failed. error: %s", err.Error())
+	}
+	if r.Merging() || r.WorkStatus() != git.Available {
+		t.Errorf("Test Failed. merge is not aborted")
+	}
+	if files, _ := Unmerged(r); len(files) != 0 {
+		t.Errorf("Test Failed. unmerged files are left")
+	}
+}
+
+// testConflictedRepo returns a repository stopped at a merge, the local and
+// upstream branches both added conflict.txt with different contents
+func testConflictedRepo() (*git.Repository, func(), error) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := testIdentity(r.AbsPath); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := testCommitFile(r.AbsPath, "conflict.txt", "local"); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := testPushFile(filepath.Dir(r.AbsPath), "conflict.txt", "remote"); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if out, err := Run(r.AbsPath, "git", []string{"fetch", "origin"}); err != nil {
+		cleanup()
+		return nil, nil, errors.New(out)
+	}
+	err = Merge(context.Background(), r, &MergeOptions{BranchName: r.State.Branch.Upstream.Name})
+	if !errors.Is(err, gerr.ErrConflictAfterMerge) {
+		cleanup()
+		return nil, nil, errors.New("merge is not conflicted")
+	}
+	return r, cleanup, nil
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// CredentialFill asks the configured git credential helpers for the
+// credentials of the url. The user is never prompted on the terminal, an error
+// is returned if none of the helpers knows the credentials.
+func CredentialFill(ctx context.Context, url string) (*git.Credentials, error) {
+	out, err := credential(ctx, "fill", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := &git.Credentials{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "username":
+			c.User = kv[1]
+		case "password":
+			c.Password = kv[1]
+		}
+	}
+	if len(c.User) == 0 && len(c.Password) == 0 {
+		return nil, fmt.Errorf("no credentials for %s", url)
+	}
+	return c, nil
+}
+
+// CredentialApprove tells the git credential helpers that the credentials
+// worked, so that they can be stored for later use
+func CredentialApprove(ctx context.Context, url string, c *git.Credentials) error {
+	_, err := credential(ctx, "approve", url, c)
+	return err
+}
+
+// CredentialReject tells the git credential helpers that the credentials
+// didn't work, so that they can be removed from the storage
+func CredentialReject(ctx context.Context, url string, c *git.Credentials) error {
+	_, err := credential(ctx, "reject", url, c)
+	return err
+}
+
+// credential runs git credential <action>, the credential description is
+// written to its standard input
+func credential(ctx context.Context, action, url string, c *git.Credentials) (string, error) {
+	var in strings.Builder
+	fmt.Fprintf(&in, "url=%s\n", url)
+	if c != nil {
+		fmt.Fprintf(&in, "username=%s\n", c.User)
+		fmt.Fprintf(&in, "password=%s\n", c.Password)
+	}
+	in.WriteString("\n")
+	cmd := exec.CommandContext(ctx, "git", "credential", action)
+	cmd.Stdin = strings.NewReader(in.String())
+	// the gui owns the terminal, helpers must not prompt the user
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return string(out), err
+}
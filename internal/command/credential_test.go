@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+func TestCredentialHelper(t *testing.T) {
+	home, err := ioutil.TempDir("", "credential-home")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(home)
+	config := "[credential]\n\thelper = store --file=" + filepath.Join(home, "credentials") + "\n"
+	if err := ioutil.WriteFile(filepath.Join(home, ".gitconfig"), []byte(config), 0600); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+	defer os.Unsetenv("GIT_CONFIG_NOSYSTEM")
+	os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	ctx := context.Background()
+	url := "https://example.com/isacikgoz/gitbatch.git"
+	if _, err := CredentialFill(ctx, url); err == nil {
+		t.Errorf("Test Failed. credentials are filled before approval")
+	}
+	c := &git.Credentials{User: "user", Password: "pass"}
+	if err := CredentialApprove(ctx, url, c); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	output, err := CredentialFill(ctx, url)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if output.User != c.User || output.Password != c.Password {
+		t.Errorf("Test Failed. output: %v, expected: %v", output, c)
+	}
+	if err := CredentialReject(ctx, url, c); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if _, err := CredentialFill(ctx, url); err == nil {
+		t.Errorf("Test Failed. credentials are filled after rejection")
+	}
+}
@@ -1,21 +1,17 @@
 package command
 
 import (
+	"context"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/isacikgoz/gitbatch/internal/git"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/transport"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
-)
-
-var (
-	fetchTryCount int
-	fetchMaxTry   = 1
 )
 
 // FetchOptions defines the rules for fetch operation
@@ -41,18 +37,17 @@ type FetchOptions struct {
 
 // Fetch branches refs from one or more other repositories, along with the
 // objects necessary to complete their histories
-func Fetch(r *git.Repository, o *FetchOptions) (err error) {
+func Fetch(ctx context.Context, r *git.Repository, o *FetchOptions) (err error) {
 	// here we configure fetch operation
 	// default mode is go-git (this may be configured)
 	mode := o.CommandMode
-	fetchTryCount = 0
 	// prune and dry run is not supported from go-git yet, rely on old friend
 	if o.Prune || o.DryRun {
 		mode = ModeLegacy
 	}
 	switch mode {
 	case ModeLegacy:
-		err = fetchWithGit(r, o)
+		err = fetchWithGit(ctx, r, o)
 		return err
 	case ModeNative:
 		// this should be the refspec as default, let's give it a try
@@ -63,7 +58,7 @@ func Fetch(r *git.Repository, o *FetchOptions) (err error) {
 		} else {
 			refspec = "+" + "refs/heads/" + r.State.Branch.Name + ":" + "/refs/remotes/" + r.State.Remote.Name + "/" + r.State.Branch.Name
 		}
-		err = fetchWithGoGit(r, o, refspec)
+		err = fetchWithGoGit(ctx, r, o, refspec)
 		return err
 	}
 	return nil
@@ -72,7 +67,7 @@ func Fetch(r *git.Repository, o *FetchOptions) (err error) {
 // fetchWithGit is simply a bare git fetch <remote> command which is flexible
 // for complex operations, but on the other hand, it ties the app to another
 // tool. To avoid that, using native implementation is preferred.
-func fetchWithGit(r *git.Repository, options *FetchOptions) (err error) {
+func fetchWithGit(ctx context.Context, r *git.Repository, options *FetchOptions) (err error) {
 	args := make([]string, 0)
 	args = append(args, "fetch")
 	// parse options to command line arguments
@@ -88,10 +83,11 @@ func fetchWithGit(r *git.Repository, options *FetchOptions) (err error) {
 	if options.DryRun {
 		args = append(args, "--dry-run")
 	}
-	if out, err := Run(r.AbsPath, "git", args); err != nil {
-		return gerr.ParseGitError(out, err)
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
 	}
 	r.SetWorkStatus(git.Success)
+	r.State.LastFetch = time.Now()
 	r.State.Message = ""
 	// till this step everything should be ok
 	return r.Refresh()
@@ -103,56 +99,47 @@ func fetchWithGit(r *git.Repository, options *FetchOptions) (err error) {
 // pattern for references on the remote side and <dst> is where those references
 // will be written locally. The + tells Git to update the reference even if it
 // isn’t a fast-forward.
-func fetchWithGoGit(r *git.Repository, options *FetchOptions, refspec string) (err error) {
+func fetchWithGoGit(ctx context.Context, r *git.Repository, options *FetchOptions, refspec string) (err error) {
 	opt := &gogit.FetchOptions{
 		RemoteName: options.RemoteName,
 		RefSpecs:   []config.RefSpec{config.RefSpec(refspec)},
 		Force:      options.Force,
 	}
-	// if any credential is given, let's add it to the git.FetchOptions
-	if options.Credentials != nil {
-		protocol, err := git.AuthProtocol(r.State.Remote)
-		if err != nil {
-			return err
-		}
-		if protocol == git.AuthProtocolHTTP || protocol == git.AuthProtocolHTTPS {
-			opt.Auth = &http.BasicAuth{
-				Username: options.Credentials.User,
-				Password: options.Credentials.Password,
-			}
-		} else {
-			return gerr.ErrInvalidAuthMethod
-		}
+	// the credentials are added to the git.FetchOptions, if there is any
+	if opt.Auth, err = authMethod(r.State.Remote, options.Credentials); err != nil {
+		return err
 	}
 	if options.Progress {
 		opt.Progress = os.Stdout
 	}
 	msg := "fetch complete, focus to see details"
-	if err := r.Repo.Fetch(opt); err != nil {
-		if err == gogit.NoErrAlreadyUpToDate {
+	if err := r.Repo.FetchContext(ctx, opt); err != nil {
+		if ctx.Err() != nil {
+			// the operation is cancelled or timed out, don't try anything else
+			return ctx.Err()
+		} else if err == gogit.NoErrAlreadyUpToDate {
 			// Already up-to-date
 			msg = err.Error()
 			// TODO: submit a PR for this kind of error, this type of catch is lame
 		} else if strings.Contains(err.Error(), "couldn't find remote ref") {
 			// we don't have remote ref, so lets pull other things.. maybe it'd be useful
 			rp := r.State.Remote.RefSpecs[0]
-			if fetchTryCount < fetchMaxTry {
-				fetchTryCount++
-				fetchWithGoGit(r, options, rp)
-			} else {
+			if refspec == rp {
 				return err
 			}
+			return fetchWithGoGit(ctx, r, options, rp)
 			// TODO: submit a PR for this kind of error, this type of catch is lame
 		} else if strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
 			// The env variable SSH_AUTH_SOCK is not defined, maybe git can handle this
-			return fetchWithGit(r, options)
+			return fetchWithGit(ctx, r, options)
 		} else if err == transport.ErrAuthenticationRequired {
 			return gerr.ErrAuthenticationRequired
 		} else {
-			return fetchWithGit(r, options)
+			return fetchWithGit(ctx, r, options)
 		}
 	}
 	r.SetWorkStatus(git.Success)
+	r.State.LastFetch = time.Now()
 
 	ref, _ := r.Repo.Head()
 	// TODO: fix this, refresh two times not cool
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"testing"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
@@ -42,7 +43,7 @@ func TestFetchWithGit(t *testing.T) {
 		{r, testFetchopts3},
 	}
 	for _, test := range tests {
-		if err := fetchWithGit(test.inp1, test.inp2); err != nil {
+		if err := fetchWithGit(context.Background(), test.inp1, test.inp2); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
@@ -64,7 +65,7 @@ func TestFetchWithGoGit(t *testing.T) {
 		{r, testFetchopts4, refspec},
 	}
 	for _, test := range tests {
-		if err := fetchWithGoGit(test.inp1, test.inp2, test.inp3); err != nil {
+		if err := fetchWithGoGit(context.Background(), test.inp1, test.inp2, test.inp3); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"regexp"
 
 	gerr "github.com/isacikgoz/gitbatch/internal/errors"
@@ -15,13 +16,16 @@ type MergeOptions struct {
 	Verbose bool
 	// With true do not show a diffstat at the end of the merge.
 	NoStat bool
+	// FFOnly refuses to merge unless the current branch can be
+	// fast-forwarded, so that no merge commit is created.
+	FFOnly bool
 	// Mode is the command mode
 	CommandMode Mode
 }
 
 // Merge incorporates changes from the named commits or branches into the
 // current branch
-func Merge(r *git.Repository, options *MergeOptions) error {
+func Merge(ctx context.Context, r *git.Repository, options *MergeOptions) error {
 
 	args := make([]string, 0)
 	args = append(args, "merge")
@@ -34,10 +38,13 @@ func Merge(r *git.Repository, options *MergeOptions) error {
 	if options.NoStat {
 		args = append(args, "-n")
 	}
+	if options.FFOnly {
+		args = append(args, "--ff-only")
+	}
 
 	ref, _ := r.Repo.Head()
-	if out, err := Run(r.AbsPath, "git", args); err != nil {
-		return gerr.ParseGitError(out, err)
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
 	}
 
 	newref, _ := r.Repo.Head()
@@ -50,6 +57,30 @@ func Merge(r *git.Repository, options *MergeOptions) error {
 	return r.Refresh()
 }
 
+// MergeAbort stops the merge in progress and restores the branch to its state
+// before the merge
+func MergeAbort(ctx context.Context, r *git.Repository) error {
+	args := []string{"merge", "--abort"}
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	r.SetWorkStatus(git.Available)
+	r.State.Message = "merge aborted"
+	return r.Refresh()
+}
+
+// MergeContinue concludes the merge in progress with the default merge
+// message once the conflicts are resolved
+func MergeContinue(ctx context.Context, r *git.Repository) error {
+	args := []string{"commit", "--no-edit"}
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = "merge concluded"
+	return r.Refresh()
+}
+
 func getMergeMessage(r *git.Repository, ref1, ref2 string) (string, error) {
 	var msg string
 	if ref1 == ref2 {
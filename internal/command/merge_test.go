@@ -1,9 +1,14 @@
 package command
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
 )
 
 func TestMerge(t *testing.T) {
@@ -22,8 +27,47 @@ func TestMerge(t *testing.T) {
 		{r, opts},
 	}
 	for _, test := range tests {
-		if err := Merge(test.inp1, test.inp2); err != nil {
+		if err := Merge(context.Background(), test.inp1, test.inp2); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
 }
+
+func TestMergeFastForwardOnly(t *testing.T) {
+	var tests = []struct {
+		localCommit bool
+		expected    error
+	}{
+		{false, nil},
+		{true, gerr.ErrDiverged},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testutil.LocalRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if test.localCommit {
+			if err := testCommitFile(r.AbsPath, "local.txt", "local"); err != nil {
+				t.Fatalf("Test Failed. error: %s", err.Error())
+			}
+		}
+		if err := testPushFile(filepath.Dir(r.AbsPath), "remote.txt", "remote"); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if out, err := Run(r.AbsPath, "git", []string{"fetch", "origin"}); err != nil {
+			t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+		}
+		before, _ := r.Repo.Head()
+		err = Merge(context.Background(), r, &MergeOptions{
+			BranchName: r.State.Branch.Upstream.Name,
+			FFOnly:     true,
+		})
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. error: %v, expected: %v", err, test.expected)
+		}
+		if after, _ := r.Repo.Head(); test.expected != nil && after.Hash() != before.Hash() {
+			t.Errorf("Test Failed. diverged branch is modified")
+		}
+		cleanup()
+	}
+}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -9,15 +10,9 @@ import (
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage"
 )
 
-var (
-	pullTryCount int
-	pullMaxTry   = 1
-)
-
 // PullOptions defines the rules for pull operation
 type PullOptions struct {
 	// Name of the remote to fetch from. Defaults to origin.
@@ -33,27 +28,47 @@ type PullOptions struct {
 	// Force allows the pull to update a local branch even when the remote
 	// branch does not descend from it.
 	Force bool
+	// Rebase the current branch on top of the upstream branch instead of
+	// merging, it is always done with git since go-git can't rebase
+	Rebase bool
+	// AutoStash stashes the local changes before the rebase and applies them
+	// afterwards
+	AutoStash bool
+	// FFOnly refuses to merge unless the current branch can be
+	// fast-forwarded, so that no merge commit is created. It is ignored if
+	// Rebase is set.
+	FFOnly bool
 	// Mode is the command mode
 	CommandMode Mode
 }
 
 // Pull incorporates changes from a remote repository into the current branch.
-func Pull(r *git.Repository, o *PullOptions) (err error) {
-	pullTryCount = 0
-
+func Pull(ctx context.Context, r *git.Repository, o *PullOptions) (err error) {
 	// here we configure pull operation
+	if o.Rebase {
+		return pullWithGit(ctx, r, o)
+	}
 	switch o.CommandMode {
 	case ModeLegacy:
-		err = pullWithGit(r, o)
+		err = pullWithGit(ctx, r, o)
 		return err
 	case ModeNative:
-		err = pullWithGoGit(r, o)
+		err = pullWithGoGit(ctx, r, o)
+		// the reference is changed meanwhile, fetch and try once more
+		if err == storage.ErrReferenceHasChanged {
+			if err = Fetch(ctx, r, &FetchOptions{
+				RemoteName:  o.RemoteName,
+				Credentials: o.Credentials,
+			}); err == nil {
+				err = pullWithGoGit(ctx, r, o)
+			}
+		}
 		return err
 	}
 	return nil
 }
 
-func pullWithGit(r *git.Repository, options *PullOptions) (err error) {
+func pullWithGit(ctx context.Context, r *git.Repository, options *PullOptions) (err error) {
 	args := make([]string, 0)
 	args = append(args, "pull")
 	// parse options to command line arguments
@@ -63,9 +78,17 @@ func pullWithGit(r *git.Repository, options *PullOptions) (err error) {
 	if options.Force {
 		args = append(args, "-f")
 	}
+	if options.Rebase {
+		args = append(args, "--rebase")
+		if options.AutoStash {
+			args = append(args, "--autostash")
+		}
+	} else if options.FFOnly {
+		args = append(args, "--ff-only")
+	}
 	ref, _ := r.Repo.Head()
-	if out, err := Run(r.AbsPath, "git", args); err != nil {
-		return gerr.ParseGitError(out, err)
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
 	}
 	newref, _ := r.Repo.Head()
 	r.SetWorkStatus(git.Success)
@@ -77,7 +100,7 @@ func pullWithGit(r *git.Repository, options *PullOptions) (err error) {
 	return r.Refresh()
 }
 
-func pullWithGoGit(r *git.Repository, options *PullOptions) (err error) {
+func pullWithGoGit(ctx context.Context, r *git.Repository, options *PullOptions) (err error) {
 	opt := &gogit.PullOptions{
 		RemoteName:   options.RemoteName,
 		SingleBranch: options.SingleBranch,
@@ -87,20 +110,9 @@ func pullWithGoGit(r *git.Repository, options *PullOptions) (err error) {
 		ref := plumbing.NewRemoteReferenceName(options.RemoteName, options.ReferenceName)
 		opt.ReferenceName = ref
 	}
-	// if any credential is given, let's add it to the git.PullOptions
-	if options.Credentials != nil {
-		protocol, err := git.AuthProtocol(r.State.Remote)
-		if err != nil {
-			return err
-		}
-		if protocol == git.AuthProtocolHTTP || protocol == git.AuthProtocolHTTPS {
-			opt.Auth = &http.BasicAuth{
-				Username: options.Credentials.User,
-				Password: options.Credentials.Password,
-			}
-		} else {
-			return gerr.ErrInvalidAuthMethod
-		}
+	// the credentials are added to the git.PullOptions, if there is any
+	if opt.Auth, err = authMethod(r.State.Remote, options.Credentials); err != nil {
+		return err
 	}
 	if options.Progress {
 		opt.Progress = os.Stdout
@@ -111,27 +123,24 @@ func pullWithGoGit(r *git.Repository, options *PullOptions) (err error) {
 	}
 	var msg string
 	ref, _ := r.Repo.Head()
-	if err = w.Pull(opt); err != nil {
-		if err == gogit.NoErrAlreadyUpToDate {
+	if err = w.PullContext(ctx, opt); err != nil {
+		if ctx.Err() != nil {
+			// the operation is cancelled or timed out, don't try anything else
+			return ctx.Err()
+		} else if err == gogit.NoErrAlreadyUpToDate {
 			// log.Error("error: " + err.Error())
 			// Already up-to-date
 			msg = err.Error()
 			// TODO: submit a PR for this kind of error, this type of catch is lame
-		} else if err == storage.ErrReferenceHasChanged && pullTryCount < pullMaxTry {
-			pullTryCount++
-			if err := Fetch(r, &FetchOptions{
-				RemoteName: options.RemoteName,
-			}); err != nil {
-				return err
-			}
-			return Pull(r, options)
+		} else if err == storage.ErrReferenceHasChanged {
+			return err
 		} else if strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
 			// The env variable SSH_AUTH_SOCK is not defined, maybe git can handle this
-			return pullWithGit(r, options)
+			return pullWithGit(ctx, r, options)
 		} else if err == transport.ErrAuthenticationRequired {
 			return gerr.ErrAuthenticationRequired
 		} else {
-			return pullWithGit(r, options)
+			return pullWithGit(ctx, r, options)
 		}
 	}
 	newref, _ := r.Repo.Head()
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"testing"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
@@ -36,7 +37,7 @@ func TestPullWithGit(t *testing.T) {
 		{r, testPullopts2},
 	}
 	for _, test := range tests {
-		if err := pullWithGit(test.inp1, test.inp2); err != nil {
+		if err := pullWithGit(context.Background(), test.inp1, test.inp2); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
@@ -56,7 +57,7 @@ func TestPullWithGoGit(t *testing.T) {
 		{r, testPullopts3},
 	}
 	for _, test := range tests {
-		if err := pullWithGoGit(test.inp1, test.inp2); err != nil {
+		if err := pullWithGoGit(context.Background(), test.inp1, test.inp2); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// PushOptions defines the rules for push operation
+type PushOptions struct {
+	// Name of the remote to push to. Defaults to origin.
+	RemoteName string
+	// RefSpecs specify what destination ref to update with what source ref.
+	// If empty, the current branch is pushed to the branch with same name.
+	RefSpecs []string
+	// Credentials holds the user and password information
+	Credentials *git.Credentials
+	// ForceWithLease allows the push to overwrite the remote branch only if
+	// it still points to the commit we have seen last.
+	ForceWithLease bool
+	// SetUpstream adds upstream (tracking) reference for the pushed branch.
+	SetUpstream bool
+	// Process logs the output to stdout
+	Progress bool
+	// Mode is the command mode
+	CommandMode Mode
+}
+
+// Push updates remote refs using local refs, while sending objects necessary
+// to complete the given refs.
+func Push(ctx context.Context, r *git.Repository, o *PushOptions) (err error) {
+	mode := o.CommandMode
+	// force-with-lease is not supported from go-git yet, rely on old friend
+	if o.ForceWithLease {
+		mode = ModeLegacy
+	}
+	switch mode {
+	case ModeLegacy:
+		err = pushWithGit(ctx, r, o)
+		return err
+	case ModeNative:
+		err = pushWithGoGit(ctx, r, o)
+		return err
+	}
+	return nil
+}
+
+// pushWithGit is simply a bare git push <remote> <refspec> command
+func pushWithGit(ctx context.Context, r *git.Repository, options *PushOptions) (err error) {
+	args := make([]string, 0)
+	args = append(args, "push")
+	// parse options to command line arguments
+	if options.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	if options.SetUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if len(options.RemoteName) > 0 {
+		args = append(args, options.RemoteName)
+		refspecs := options.RefSpecs
+		if len(refspecs) == 0 && r.State.Branch != nil {
+			refspecs = []string{r.State.Branch.Name}
+		}
+		args = append(args, refspecs...)
+	}
+	pushables := pushableCount(r)
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = getPushMessage(pushables)
+	return r.Refresh()
+}
+
+// pushWithGoGit is the primary push method, if no refspec is given it pushes
+// the current branch to the remote branch with the same name
+func pushWithGoGit(ctx context.Context, r *git.Repository, options *PushOptions) (err error) {
+	opt := &gogit.PushOptions{
+		RemoteName: options.RemoteName,
+	}
+	for _, rs := range options.RefSpecs {
+		opt.RefSpecs = append(opt.RefSpecs, config.RefSpec(rs))
+	}
+	if len(opt.RefSpecs) == 0 && r.State.Branch != nil {
+		ref := "refs/heads/" + r.State.Branch.Name
+		opt.RefSpecs = []config.RefSpec{config.RefSpec(ref + ":" + ref)}
+	}
+	// the credentials are added to the git.PushOptions, if there is any
+	if opt.Auth, err = authMethod(r.State.Remote, options.Credentials); err != nil {
+		return err
+	}
+	if options.Progress {
+		opt.Progress = os.Stdout
+	}
+	pushables := pushableCount(r)
+	if err := r.Repo.PushContext(ctx, opt); err != nil {
+		if ctx.Err() != nil {
+			// the operation is cancelled or timed out, don't try anything else
+			return ctx.Err()
+		} else if err == gogit.NoErrAlreadyUpToDate {
+			// Already up-to-date
+			pushables = 0
+		} else if strings.Contains(err.Error(), "SSH_AUTH_SOCK") {
+			// The env variable SSH_AUTH_SOCK is not defined, maybe git can handle this
+			return pushWithGit(ctx, r, options)
+		} else if err == transport.ErrAuthenticationRequired {
+			return gerr.ErrAuthenticationRequired
+		} else {
+			return pushWithGit(ctx, r, options)
+		}
+	}
+	if options.SetUpstream && r.State.Branch != nil {
+		if err := setUpstream(r, options.RemoteName, r.State.Branch.Name); err != nil {
+			return err
+		}
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = getPushMessage(pushables)
+	return r.Refresh()
+}
+
+// setUpstream writes the tracking configuration of the branch, same as what
+// "git push --set-upstream" does after a successful push
+func setUpstream(r *git.Repository, remote, branch string) error {
+	cfg, err := r.Repo.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Branches[branch] = &config.Branch{
+		Name:   branch,
+		Remote: remote,
+		Merge:  plumbing.NewBranchReferenceName(branch),
+	}
+	return r.Repo.SetConfig(cfg)
+}
+
+// pushableCount returns the pushable commit count of the current branch before
+// the push operation so that it can be reported afterwards, it is negative if
+// the count is unknown
+func pushableCount(r *git.Repository) int {
+	if r.State.Branch == nil || !r.State.Branch.Counted() {
+		return -1
+	}
+	return r.State.Branch.Pushables
+}
+
+func getPushMessage(pushables int) string {
+	switch {
+	case pushables == 0:
+		return "already up-to-date"
+	case pushables < 0:
+		return "push complete"
+	}
+	return strconv.Itoa(pushables) + " commit(s) pushed"
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestPushWithGit(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	if err := testCommit(r, "local commit"); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		input    *PushOptions
+		expected string
+	}{
+		{&PushOptions{RemoteName: "origin"}, "1 commit(s) pushed"},
+		{&PushOptions{RemoteName: "origin", ForceWithLease: true}, "already up-to-date"},
+	}
+	for _, test := range tests {
+		if err := Push(context.Background(), r, test.input); err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+		} else if r.State.Message != test.expected {
+			t.Errorf("Test Failed. message: %s, expected: %s", r.State.Message, test.expected)
+		}
+	}
+}
+
+func TestPushWithGoGit(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	if err := testCommit(r, "local commit"); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		input    *PushOptions
+		expected string
+	}{
+		{&PushOptions{RemoteName: "origin", CommandMode: ModeNative}, "1 commit(s) pushed"},
+		{&PushOptions{RemoteName: "origin", CommandMode: ModeNative}, "already up-to-date"},
+	}
+	for _, test := range tests {
+		if err := pushWithGoGit(context.Background(), r, test.input); err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+		} else if r.State.Message != test.expected {
+			t.Errorf("Test Failed. message: %s, expected: %s", r.State.Message, test.expected)
+		}
+	}
+	if r.State.Branch.Pushables != 0 {
+		t.Errorf("Test Failed. pushables: %d, expected: 0", r.State.Branch.Pushables)
+	}
+}
+
+func TestGetPushMessage(t *testing.T) {
+	var tests = []struct {
+		input    int
+		expected string
+	}{
+		{0, "already up-to-date"},
+		{-1, "push complete"},
+		{3, "3 commit(s) pushed"},
+	}
+	for _, test := range tests {
+		if output := getPushMessage(test.input); output != test.expected {
+			t.Errorf("Test Failed. %d inputted, output: %s, expected: %s", test.input, output, test.expected)
+		}
+	}
+}
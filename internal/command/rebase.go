@@ -0,0 +1,72 @@
+package command
+
+import (
+	"context"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// RebaseOptions defines the rules of a rebase operation
+type RebaseOptions struct {
+	// Upstream is the branch to rebase the current branch onto
+	Upstream string
+	// AutoStash stashes the local changes before the rebase and applies them
+	// afterwards
+	AutoStash bool
+	// Mode is the command mode
+	CommandMode Mode
+}
+
+// Rebase reapplies the commits of the current branch on top of the upstream.
+// If it stops with conflicts, the repository is left in the rebasing state so
+// that it can be resolved or aborted.
+func Rebase(ctx context.Context, r *git.Repository, options *RebaseOptions) error {
+	args := make([]string, 0)
+	args = append(args, "rebase")
+	if options.AutoStash {
+		args = append(args, "--autostash")
+	}
+	if len(options.Upstream) > 0 {
+		args = append(args, options.Upstream)
+	}
+
+	ref, _ := r.Repo.Head()
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+
+	newref, _ := r.Repo.Head()
+	r.SetWorkStatus(git.Success)
+	msg, err := getMergeMessage(r, ref.Hash().String(), newref.Hash().String())
+	if err != nil {
+		msg = "couldn't get stat"
+	}
+	r.State.Message = msg
+	return r.Refresh()
+}
+
+// RebaseContinue applies the rest of the commits once the conflicts of the
+// stopped commit are resolved. It may stop again at another conflict.
+func RebaseContinue(ctx context.Context, r *git.Repository) error {
+	// the message of the stopped commit is kept without opening an editor
+	args := []string{"-c", "core.editor=true", "rebase", "--continue"}
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = "rebase concluded"
+	return r.Refresh()
+}
+
+// RebaseAbort stops the rebase in progress and restores the branch to its
+// state before the rebase
+func RebaseAbort(ctx context.Context, r *git.Repository) error {
+	args := []string{"rebase", "--abort"}
+	if out, err := RunContext(ctx, r.AbsPath, "git", args); err != nil {
+		return gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	r.SetWorkStatus(git.Available)
+	r.State.Message = "rebase aborted"
+	return r.Refresh()
+}
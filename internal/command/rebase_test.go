@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestRebase(t *testing.T) {
+	var tests = []struct {
+		remoteFile string
+		expected   error
+	}{
+		{"remote.txt", nil},
+		{"local.txt", gerr.ErrRebaseConflict},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testutil.LocalRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		root := filepath.Dir(r.AbsPath)
+		if err := testIdentity(r.AbsPath); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := testCommitFile(r.AbsPath, "local.txt", "local"); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := testPushFile(root, test.remoteFile, "remote"); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if out, err := Run(r.AbsPath, "git", []string{"fetch", "origin"}); err != nil {
+			t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+		}
+		err = Rebase(context.Background(), r, &RebaseOptions{Upstream: r.State.Branch.Upstream.Name})
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. error: %v, expected: %v", err, test.expected)
+		}
+		if rebasing := r.Rebasing(); rebasing != (test.expected != nil) {
+			t.Errorf("Test Failed. rebasing: %t, expected: %t", rebasing, test.expected != nil)
+		}
+		if test.expected != nil {
+			if err := RebaseAbort(context.Background(), r); err != nil {
+				t.Errorf("Test Failed. error: %s", err.Error())
+			}
+			if r.Rebasing() {
+				t.Errorf("Test Failed. rebase is not aborted")
+			}
+		}
+		cleanup()
+	}
+}
+
+func TestPullRebase(t *testing.T) {
+	var tests = []struct {
+		input    *PullOptions
+		expected error
+	}{
+		{&PullOptions{RemoteName: "origin", Rebase: true}, gerr.ErrMergeAbortedTryCommit},
+		{&PullOptions{RemoteName: "origin", Rebase: true, AutoStash: true}, nil},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testutil.LocalRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		root := filepath.Dir(r.AbsPath)
+		if err := testIdentity(r.AbsPath); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := testCommitFile(r.AbsPath, "local.txt", "local"); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := testPushFile(root, "remote.txt", "remote"); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		// uncommitted change to be stashed
+		if err := ioutil.WriteFile(filepath.Join(r.AbsPath, "local.txt"), []byte("changed"), 0644); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if err := Pull(context.Background(), r, test.input); !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. error: %v, expected: %v", err, test.expected)
+		}
+		if out, _ := ioutil.ReadFile(filepath.Join(r.AbsPath, "local.txt")); string(out) != "changed" {
+			t.Errorf("Test Failed. local change is lost")
+		}
+		cleanup()
+	}
+}
+
+// testIdentity configures the committer of the rebased commits
+func testIdentity(dir string) error {
+	if _, err := Run(dir, "git", []string{"config", "user.name", "gitbatch"}); err != nil {
+		return err
+	}
+	_, err := Run(dir, "git", []string{"config", "user.email", "gitbatch@localhost"})
+	return err
+}
+
+// testCommitFile writes the file and commits it
+func testCommitFile(dir, name, content string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		return err
+	}
+	if _, err := Run(dir, "git", []string{"add", name}); err != nil {
+		return err
+	}
+	_, err := Run(dir, "git", testCommitArgs("add "+name))
+	return err
+}
+
+// testPushFile commits the file to the source repository of the local remote
+// and pushes it to the remote
+func testPushFile(root, name, content string) error {
+	src := filepath.Join(root, "src")
+	if err := testCommitFile(src, name, content); err != nil {
+		return err
+	}
+	_, err := Run(src, "git", []string{"push", filepath.Join(root, "remote.git"), "HEAD"})
+	return err
+}
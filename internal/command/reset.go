@@ -95,6 +95,9 @@ func resetAllWithGit(r *git.Repository, option *ResetOptions) error {
 	if len(option.ResetType) > 0 {
 		args = append(args, "--"+string(option.ResetType))
 	}
+	if len(option.Hash) > 0 {
+		args = append(args, option.Hash)
+	}
 	_, err := Run(r.AbsPath, "git", args)
 	if err != nil {
 		return fmt.Errorf("could not reset all: %v", err)
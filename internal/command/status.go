@@ -7,37 +7,35 @@ import (
 	"sort"
 	"strings"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/isacikgoz/gitbatch/internal/git"
 )
 
 var (
-	statusCmdMode string
+	statusCmdMode = statusCmdModeLegacy
 
 	statusCmdModeLegacy = "git"
 	statusCmdModeNative = "go-git"
 )
 
-func shortStatus(r *git.Repository, option string) string {
-	args := make([]string, 0)
-	args = append(args, "status")
-	args = append(args, option)
-	args = append(args, "--short")
-	out, err := Run(r.AbsPath, "git", args)
-	if err != nil {
-		return "?"
+// SetStatusMode selects the implementation of Status, either "git" or "go-git"
+func SetStatusMode(mode string) error {
+	switch mode {
+	case statusCmdModeLegacy, statusCmdModeNative:
+		statusCmdMode = mode
+		return nil
 	}
-	return out
+	return fmt.Errorf("unrecognized status mode: %s", mode)
 }
 
 // Status returns the dirty files
 func Status(r *git.Repository) ([]*git.File, error) {
-	// in case we want configure Status command externally
-	mode := ModeLegacy
-
-	switch mode {
-	case ModeLegacy:
+	switch statusCmdMode {
+	case statusCmdModeLegacy:
 		return statusWithGit(r)
-	case ModeNative:
+	case statusCmdModeNative:
 		return statusWithGoGit(r)
 	}
 	return nil, fmt.Errorf("unhandled status operation")
@@ -56,30 +54,77 @@ func PlainStatus(r *git.Repository) (string, error) {
 	return output, err
 }
 
-// LoadFiles function simply commands a git status and collects output in a
-// structured way
+// statusWithGit collects the output of "git status --porcelain=v2 -z" in a
+// structured way. The paths are NUL terminated and never quoted, so that the
+// names with spaces, unicode or arrows are kept as is.
 func statusWithGit(r *git.Repository) ([]*git.File, error) {
-	files := make([]*git.File, 0)
-	output := shortStatus(r, "--untracked-files=all")
-	if len(output) == 0 {
-		return files, nil
-	}
-	fileslist := strings.Split(output, "\n")
-	for _, file := range fileslist {
-		x := byte(file[0])
-		y := byte(file[1])
-		relativePathRegex := regexp.MustCompile(`[(\w|/|.|\-)]+`)
-		path := relativePathRegex.FindString(file[2:])
+	args := []string{"status", "--porcelain=v2", "-z", "--untracked-files=all"}
+	out, err := Run(r.AbsPath, "git", args)
+	if err != nil {
+		return nil, gerr.NewGitError(r.AbsPath, args, out, err)
+	}
+	files := parseStatus(r.AbsPath, out)
+	sort.Sort(git.FilesAlphabetical(files))
+	return files, nil
+}
 
+// parseStatus parses the entries of the porcelain v2 format, see git-status(1)
+func parseStatus(dir, out string) []*git.File {
+	files := make([]*git.File, 0)
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		if len(e) < 3 {
+			continue
+		}
+		var xy, path, orig string
+		switch e[0] {
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(e, " ", 9)
+			if len(fields) < 9 {
+				continue
+			}
+			xy, path = fields[1], fields[8]
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, the
+			// original path is the next entry
+			fields := strings.SplitN(e, " ", 10)
+			if len(fields) < 10 || i+1 >= len(entries) {
+				continue
+			}
+			xy, path = fields[1], fields[9]
+			i++
+			orig = entries[i]
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(e, " ", 11)
+			if len(fields) < 11 {
+				continue
+			}
+			xy, path = fields[1], fields[10]
+		case '?', '!':
+			xy, path = e[:1]+e[:1], e[2:]
+		default:
+			continue
+		}
 		files = append(files, &git.File{
-			Name:    path,
-			AbsPath: r.AbsPath + string(os.PathSeparator) + path,
-			X:       git.FileStatus(x),
-			Y:       git.FileStatus(y),
+			Name:     path,
+			AbsPath:  dir + string(os.PathSeparator) + path,
+			X:        statusCode(xy[0]),
+			Y:        statusCode(xy[1]),
+			OrigName: orig,
 		})
 	}
-	sort.Sort(git.FilesAlphabetical(files))
-	return files, nil
+	return files
+}
+
+// the unmodified side is shown as a dot in porcelain v2
+func statusCode(c byte) git.FileStatus {
+	if c == '.' {
+		return git.StatusNotupdated
+	}
+	return git.FileStatus(c)
 }
 
 func statusWithGoGit(r *git.Repository) ([]*git.File, error) {
@@ -92,14 +137,96 @@ func statusWithGoGit(r *git.Repository) ([]*git.File, error) {
 	if err != nil {
 		return files, err
 	}
+	renames, err := detectRenames(r, s)
+	if err != nil {
+		return files, err
+	}
 	for k, v := range s {
-		files = append(files, &git.File{
+		if _, ok := renames.from[k]; ok {
+			continue
+		}
+		f := &git.File{
 			Name:    k,
 			AbsPath: r.AbsPath + string(os.PathSeparator) + k,
 			X:       git.FileStatus(v.Staging),
 			Y:       git.FileStatus(v.Worktree),
-		})
+		}
+		if orig, ok := renames.to[k]; ok {
+			f.X, f.OrigName = git.StatusRenamed, orig
+		}
+		files = append(files, f)
 	}
 	sort.Sort(git.FilesAlphabetical(files))
 	return files, nil
 }
+
+// renames maps the new paths of the renamed files to the old ones and vice
+// versa
+type renames struct {
+	to   map[string]string
+	from map[string]string
+}
+
+// detectRenames pairs the files deleted from the index with the ones added to
+// it having the same content, like git does for the exact renames. go-git
+// reports them as separate deletions and additions.
+func detectRenames(r *git.Repository, s gogit.Status) (*renames, error) {
+	rn := &renames{
+		to:   make(map[string]string),
+		from: make(map[string]string),
+	}
+	deleted := make([]string, 0)
+	added := make([]string, 0)
+	for k, v := range s {
+		switch v.Staging {
+		case gogit.Deleted:
+			deleted = append(deleted, k)
+		case gogit.Added:
+			added = append(added, k)
+		}
+	}
+	if len(deleted) == 0 || len(added) == 0 {
+		return rn, nil
+	}
+	head, err := r.Repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := r.Repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := r.Repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	// the deleted files are looked up by their content, the paths are sorted
+	// so that the pairs are the same on every call
+	sort.Strings(deleted)
+	sort.Strings(added)
+	byHash := make(map[plumbing.Hash][]string)
+	for _, path := range deleted {
+		f, err := tree.File(path)
+		if err != nil {
+			continue
+		}
+		byHash[f.Hash] = append(byHash[f.Hash], path)
+	}
+	for _, path := range added {
+		e, err := idx.Entry(path)
+		if err != nil {
+			continue
+		}
+		candidates := byHash[e.Hash]
+		if len(candidates) == 0 {
+			continue
+		}
+		rn.to[path], rn.from[candidates[0]] = candidates[0], path
+		byHash[e.Hash] = candidates[1:]
+	}
+	return rn, nil
+}
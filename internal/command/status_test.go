@@ -1,9 +1,12 @@
 package command
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
 )
 
 func TestStatusWithGit(t *testing.T) {
@@ -49,3 +52,75 @@ func TestStatusWithGoGit(t *testing.T) {
 		}
 	}
 }
+
+func TestParseStatus(t *testing.T) {
+	out := "1 .M N... 100644 100644 100644 3b18e51 3b18e51 file with spaces.txt\x00" +
+		"2 R. N... 100644 100644 100644 3b18e51 3b18e51 R100 new -> name\x00old name\x00" +
+		"u UU N... 100644 100644 100644 100644 3b18e51 3b18e51 3b18e51 dir/conflict.txt\x00" +
+		"? ünïcödé.txt\x00"
+	var tests = []struct {
+		name string
+		orig string
+		x    git.FileStatus
+		y    git.FileStatus
+	}{
+		{"file with spaces.txt", "", git.StatusNotupdated, git.StatusModified},
+		{"new -> name", "old name", git.StatusRenamed, git.StatusNotupdated},
+		{"dir/conflict.txt", "", git.StatusUpdated, git.StatusUpdated},
+		{"ünïcödé.txt", "", git.StatusUntracked, git.StatusUntracked},
+	}
+	files := parseStatus("/repo", out)
+	if len(files) != len(tests) {
+		t.Fatalf("Test Failed. files: %d, expected: %d", len(files), len(tests))
+	}
+	for i, test := range tests {
+		f := files[i]
+		if f.Name != test.name || f.OrigName != test.orig || f.X != test.x || f.Y != test.y {
+			t.Errorf("Test Failed. output: %q %q %c%c, expected: %q %q %c%c", f.Name, f.OrigName, f.X, f.Y, test.name, test.orig, test.x, test.y)
+		}
+		if f.AbsPath != filepath.Join("/repo", test.name) {
+			t.Errorf("Test Failed. path: %s", f.AbsPath)
+		}
+	}
+}
+
+func TestStatusRename(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	if err := testIdentity(r.AbsPath); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if err := testCommitFile(r.AbsPath, "old.txt", "content"); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if out, err := Run(r.AbsPath, "git", []string{"mv", "old.txt", "new file.txt"}); err != nil {
+		t.Fatalf("Test Failed. error: %s", out)
+	}
+	if err := ioutil.WriteFile(filepath.Join(r.AbsPath, "ünïcödé.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		status func(*git.Repository) ([]*git.File, error)
+	}{
+		{statusWithGit},
+		{statusWithGoGit},
+	}
+	for _, test := range tests {
+		files, err := test.status(r)
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if len(files) != 2 {
+			t.Fatalf("Test Failed. files: %d, expected: 2", len(files))
+		}
+		if f := files[0]; f.Name != "new file.txt" || f.OrigName != "old.txt" || f.X != git.StatusRenamed {
+			t.Errorf("Test Failed. output: %q %q %c, expected a rename", f.Name, f.OrigName, f.X)
+		}
+		if f := files[1]; f.Name != "ünïcödé.txt" || f.X != git.StatusUntracked {
+			t.Errorf("Test Failed. output: %q %c, expected untracked", f.Name, f.X)
+		}
+	}
+}
@@ -1,10 +1,19 @@
 package errors
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"regexp"
 	"strings"
+	"syscall"
 )
 
-// GitError is the errors from git package
+// GitError is the errors from git package, it is also the category of the
+// failed git commands
 type GitError string
 
 const (
@@ -37,6 +46,15 @@ const (
 	// ErrConflictAfterMerge is thrown when a conflict occurs at merging two
 	// references
 	ErrConflictAfterMerge GitError = ("conflict while merging")
+	// ErrRebaseConflict is thrown when a rebase stops at a commit that can't
+	// be applied cleanly, the rebase has to be continued or aborted
+	ErrRebaseConflict GitError = ("conflict while rebasing")
+	// ErrDiverged is thrown when a fast-forward only merge is not possible
+	// since the branch and its upstream have diverged
+	ErrDiverged GitError = ("diverged from upstream")
+	// ErrBranchNotMerged is thrown when a branch is not deleted since it has
+	// commits that are not merged into the base branch
+	ErrBranchNotMerged GitError = ("branch is not fully merged")
 	// ErrUnmergedFiles possibly occurs after a conflict
 	ErrUnmergedFiles GitError = ("unmerged files detected")
 	// ErrReferenceBroken thrown when unable to resolve reference
@@ -48,6 +66,12 @@ const (
 	// ErrUserEmailNotSet is thrown if there is no configured user email while
 	// commit command
 	ErrUserEmailNotSet GitError = ("user email not configured")
+	// ErrConnectionFailed is thrown when the connection to the remote is lost
+	// or the remote server is temporarily unavailable
+	ErrConnectionFailed GitError = ("connection failed")
+	// ErrHeadMoved is thrown when the repository is changed after an operation
+	// so that the operation can't be undone safely
+	ErrHeadMoved GitError = ("HEAD has moved since the operation")
 	// ErrUnclassified is unconsidered error type
 	ErrUnclassified GitError = ("unclassified error")
 	// NoErrIterationHalted is thrown for catching stops in interators
@@ -58,27 +82,184 @@ func (e GitError) Error() string {
 	return string(e)
 }
 
+// Error is a failed git command. It is classified into one of the GitError
+// categories and errors.Is reports true for its category. The output of the
+// command is kept so that the failure can be diagnosed.
+type Error struct {
+	// Category is the classification of the failure e.g. ErrUnmergedFiles
+	Category GitError
+	// Args are the arguments of the git command
+	Args []string
+	// Path is the directory that the command ran in
+	Path string
+	// ExitCode is the exit code of the command, -1 if it is unknown
+	ExitCode int
+	// Output is the combined stdout and stderr of the command
+	Output string
+	// Err is the error of the command execution
+	Err error
+}
+
+func (e *Error) Error() string {
+	return string(e.Category)
+}
+
+// Is reports whether the target is the category of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Category
+}
+
+// Unwrap returns the error of the command execution
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Detail returns the category, the command, its exit code and its output
+func (e *Error) Detail() string {
+	var sb strings.Builder
+	fmt.Fprintln(&sb, e.Category)
+	if len(e.Args) > 0 {
+		fmt.Fprintf(&sb, "command: git %s\n", strings.Join(e.Args, " "))
+	}
+	if len(e.Path) > 0 {
+		fmt.Fprintf(&sb, "path: %s\n", e.Path)
+	}
+	if e.ExitCode >= 0 {
+		fmt.Fprintf(&sb, "exit code: %d\n", e.ExitCode)
+	}
+	if len(e.Output) > 0 {
+		fmt.Fprintf(&sb, "\n%s\n", e.Output)
+	}
+	return sb.String()
+}
+
+// Detail returns the detailed description of the error if it has one, the
+// message of the error otherwise
+func Detail(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Detail()
+	}
+	return err.Error()
+}
+
 // ParseGitError takes git output as an input and tries to find some meaningful
 // errors can be used by the app
 func ParseGitError(out string, err error) error {
+	return NewGitError("", nil, out, err)
+}
+
+// NewGitError classifies the output of the git command that failed with err.
+// The command, its directory and output are kept in the returned error.
+func NewGitError(path string, args []string, out string, err error) error {
+	// a cancelled or timed out command is not a git error
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	code := -1
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		code = ee.ExitCode()
+	}
+	return &Error{
+		Category: classify(out),
+		Args:     args,
+		Path:     path,
+		ExitCode: code,
+		Output:   out,
+		Err:      err,
+	}
+}
+
+// classify finds the category of the failure from the output of git
+func classify(out string) GitError {
 	if strings.Contains(out, "error: Your local changes to the following files would be overwritten by merge") {
 		return ErrMergeAbortedTryCommit
 	} else if strings.Contains(out, "ERROR: Repository not found") {
 		return ErrRemoteNotFound
 	} else if strings.Contains(out, "for your current branch, you must specify a branch on the command line") {
 		return ErrRemoteBranchNotSpecified
+	} else if strings.Contains(out, "cannot pull with rebase: You have unstaged changes") ||
+		strings.Contains(out, "cannot rebase: You have unstaged changes") {
+		return ErrMergeAbortedTryCommit
+	} else if strings.Contains(strings.ToLower(out), "could not apply") ||
+		strings.Contains(out, "git rebase --continue") {
+		return ErrRebaseConflict
+	} else if strings.Contains(out, "Not possible to fast-forward") {
+		return ErrDiverged
+	} else if strings.Contains(out, "is not fully merged") {
+		return ErrBranchNotMerged
 	} else if strings.Contains(out, "Automatic merge failed; fix conflicts and then commit the result") {
 		return ErrConflictAfterMerge
 	} else if strings.Contains(out, "error: Pulling is not possible because you have unmerged files.") {
 		return ErrUnmergedFiles
 	} else if strings.Contains(out, "unable to resolve reference") {
 		return ErrReferenceBroken
-	} else if strings.Contains(out, "git config --global add user.email") {
+	} else if strings.Contains(out, "git config --global add user.email") ||
+		strings.Contains(out, "Please tell me who you are") {
 		return ErrUserEmailNotSet
 	} else if strings.Contains(out, "Permission denied (publickey)") {
 		return ErrPermissionDenied
 	} else if strings.Contains(out, "would be overwritten by merge") {
 		return ErrOverwrittenByMerge
+	} else if transientOutput(out) {
+		return ErrConnectionFailed
 	}
 	return ErrUnclassified
 }
+
+// the messages of the network failures that may not occur on a second try, the
+// server errors are reported with their http status codes
+var (
+	transientMessages = []string{
+		"connection reset",
+		"connection refused",
+		"couldn't connect to server",
+		"connection timed out",
+		"operation timed out",
+		"i/o timeout",
+		"tls handshake timeout",
+		"unexpected eof",
+		"early eof",
+		"the remote end hung up unexpectedly",
+		"temporary failure in name resolution",
+	}
+	serverError = regexp.MustCompile(`(error|status code):? 5\d\d`)
+)
+
+func transientOutput(out string) bool {
+	out = strings.ToLower(out)
+	for _, m := range transientMessages {
+		if strings.Contains(out, m) {
+			return true
+		}
+	}
+	return serverError.MatchString(out)
+}
+
+// IsTransient reports whether the error is likely caused by a temporary network
+// problem, so that the operation can be tried again. Cancellations, timeouts
+// of the job itself and authentication failures are never transient.
+func IsTransient(err error) bool {
+	switch err {
+	case nil, context.Canceled, context.DeadlineExceeded:
+		return false
+	case io.ErrUnexpectedEOF:
+		return true
+	}
+	if errors.Is(err, ErrConnectionFailed) {
+		return true
+	}
+	var e *Error
+	if _, ok := err.(GitError); ok || errors.As(err, &e) {
+		return false
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	return transientOutput(err.Error())
+}
@@ -1,19 +1,91 @@
 package errors
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
 	"testing"
 )
 
 func TestParseGitError(t *testing.T) {
 	var tests = []struct {
-		input    string
+		inp1     string
+		inp2     error
 		expected error
 	}{
-		{"", ErrUnclassified},
+		{"", nil, ErrUnclassified},
+		{"", errors.New("exit status 1"), ErrUnclassified},
+		{"", context.Canceled, context.Canceled},
+		{"", context.DeadlineExceeded, context.DeadlineExceeded},
+		{"fatal: unable to access 'https://gitlab.com/isacikgoz/dirty-repo.git/': The requested URL returned error: 503", errors.New("exit status 128"), ErrConnectionFailed},
+		{"fatal: the remote end hung up unexpectedly", errors.New("exit status 128"), ErrConnectionFailed},
+		{"CONFLICT (content): Merge conflict in README.md\nerror: could not apply 1a2b3c4... update readme", errors.New("exit status 1"), ErrRebaseConflict},
+		{"error: cannot pull with rebase: You have unstaged changes.", errors.New("exit status 128"), ErrMergeAbortedTryCommit},
+		{"fatal: Not possible to fast-forward, aborting.", errors.New("exit status 128"), ErrDiverged},
+		{"error: The branch 'feature' is not fully merged.", errors.New("exit status 1"), ErrBranchNotMerged},
+		{"Committer identity unknown\n\n*** Please tell me who you are.", errors.New("exit status 128"), ErrUserEmailNotSet},
 	}
 	for _, test := range tests {
-		if output := ParseGitError(test.input, nil); output != test.expected {
+		if output := ParseGitError(test.inp1, test.inp2); !errors.Is(output, test.expected) {
 			t.Errorf("Test Failed. %s expected, output: %s", test.expected.Error(), output.Error())
 		}
 	}
 }
+
+func TestNewGitError(t *testing.T) {
+	out, err := exec.Command("git", "merge", "--no-such-option").CombinedOutput()
+	if err == nil {
+		t.Fatalf("Test Failed. command is expected to fail")
+	}
+	args := []string{"merge", "--no-such-option"}
+	e := NewGitError("/tmp", args, string(out), err)
+	var ge *Error
+	if !errors.As(e, &ge) {
+		t.Fatalf("Test Failed. %T is not an *Error", e)
+	}
+	if ge.ExitCode != 129 {
+		t.Errorf("Test Failed. exit code: %d, expected: %d", ge.ExitCode, 129)
+	}
+	if ge.Output != string(out) || ge.Path != "/tmp" {
+		t.Errorf("Test Failed. output or path is not preserved")
+	}
+	if !errors.Is(e, ge.Category) || errors.Is(e, ErrAuthenticationRequired) {
+		t.Errorf("Test Failed. %s does not match its category only", e)
+	}
+	detail := Detail(e)
+	for _, s := range []string{"git merge --no-such-option", "path: /tmp", "exit code: 129", string(out)} {
+		if !strings.Contains(detail, s) {
+			t.Errorf("Test Failed. %q is missing in the detail", s)
+		}
+	}
+	if output := Detail(ErrUnmergedFiles); output != ErrUnmergedFiles.Error() {
+		t.Errorf("Test Failed. %s expected, output: %s", ErrUnmergedFiles.Error(), output)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	var tests = []struct {
+		input    error
+		expected bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{ErrAuthenticationRequired, false},
+		{ErrConnectionFailed, true},
+		{&Error{Category: ErrConnectionFailed}, true},
+		{&Error{Category: ErrUnmergedFiles}, false},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{errors.New("unexpected requesting \"https://gitlab.com/info/refs\" status code: 502"), true},
+		{errors.New("unexpected requesting \"https://gitlab.com/info/refs\" status code: 404"), false},
+		{errors.New("repository not found"), false},
+	}
+	for _, test := range tests {
+		if output := IsTransient(test.input); output != test.expected {
+			t.Errorf("Test Failed. %v inputted, output: %t, expected: %t", test.input, output, test.expected)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package git
 
 import (
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 // Credentials holds user credentials to authenticate and authorize while
@@ -12,6 +16,57 @@ type Credentials struct {
 	User string
 	// Password is the secret information required for authentication
 	Password string
+	// PrivateKey is the path of the private key for SSH remotes, if it is
+	// empty the ssh-agent or the default key of the user is used
+	PrivateKey string
+	// Passphrase decrypts the private key if it is protected
+	Passphrase string
+	// Token is a personal access token for HTTP remotes, it is sent as the
+	// password of the user unless Bearer is set
+	Token string
+	// Bearer sends the token in the Authorization: Bearer header instead
+	Bearer bool
+}
+
+// tokens are the access tokens of the hosts that are used for the HTTP remotes
+// automatically
+var (
+	tokens     = make(map[string]*Credentials)
+	tokenMutex = &sync.Mutex{}
+)
+
+// SetToken registers the token credentials of the host
+func SetToken(host string, c *Credentials) {
+	tokenMutex.Lock()
+	defer tokenMutex.Unlock()
+	tokens[strings.ToLower(host)] = c
+}
+
+// Token returns the token credentials of the host. If none is registered, the
+// GITBATCH_TOKEN_<HOST> environment variable is looked up e.g.
+// GITBATCH_TOKEN_GITLAB_COM for gitlab.com. Nil is returned if there is none.
+func Token(host string) *Credentials {
+	tokenMutex.Lock()
+	c, ok := tokens[strings.ToLower(host)]
+	tokenMutex.Unlock()
+	if ok {
+		return c
+	}
+	if t := os.Getenv(TokenEnvironmentVariable(host)); len(t) > 0 {
+		return &Credentials{Token: t}
+	}
+	return nil
+}
+
+// TokenEnvironmentVariable returns the name of the environment variable that
+// holds the token of the host
+func TokenEnvironmentVariable(host string) string {
+	return "GITBATCH_TOKEN_" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, host)
 }
 
 // Schemes for authentication
@@ -25,8 +80,9 @@ const (
 // various auth protocols require different kind of authentication
 func AuthProtocol(r *Remote) (p string, err error) {
 	ur := r.URL[0]
-	if strings.HasPrefix(ur, "git@") {
-		return "ssh", nil
+	// scp-like syntax is ssh, e.g. git@github.com:user/repo.git
+	if _, ok := scpHost(ur); ok {
+		return AuthProtocolSSH, nil
 	}
 	u, err := url.Parse(ur)
 	if err != nil {
@@ -34,3 +90,54 @@ func AuthProtocol(r *Remote) (p string, err error) {
 	}
 	return u.Scheme, err
 }
+
+// RemoteHost returns the host name of the given remote's URL. Local remotes
+// do not have a host, an empty string is returned for them
+func RemoteHost(r *Remote) (h string, err error) {
+	if r == nil || len(r.URL) == 0 {
+		return h, nil
+	}
+	ur := r.URL[0]
+	if host, ok := scpHost(ur); ok {
+		return host, nil
+	}
+	u, err := url.Parse(ur)
+	if err != nil {
+		return h, err
+	}
+	return u.Hostname(), nil
+}
+
+// scpHost returns the host of the scp-like syntax [user@]host:path, e.g.
+// git@github.com:user/repo.git. Like git, it is scp-like only if there is no
+// scheme and the colon comes before any slash.
+func scpHost(ur string) (string, bool) {
+	if strings.Contains(ur, "://") {
+		return "", false
+	}
+	i := strings.Index(ur, ":")
+	if i <= 0 || strings.Contains(ur[:i], "/") {
+		return "", false
+	}
+	host := ur[:i]
+	if j := strings.LastIndex(host, "@"); j >= 0 {
+		host = host[j+1:]
+	}
+	return host, true
+}
+
+// DefaultPrivateKey returns the path of the first private key that exists in
+// the .ssh directory of the user, an empty string if there is none
+func DefaultPrivateKey() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		path := filepath.Join(home, ".ssh", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
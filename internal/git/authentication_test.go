@@ -1,26 +1,85 @@
 package git
 
 import (
+	"os"
 	"testing"
 )
 
 func TestAuthProtocol(t *testing.T) {
 	var tests = []struct {
-		input *Remote
+		input    *Remote
+		expected string
 	}{
 		{&Remote{
 			URL: []string{"https://gitlab.com/isacikgoz/dirty-repo.git", ""},
-		}},
+		}, AuthProtocolHTTPS},
 		{&Remote{
 			URL: []string{"http://gitlab.com/isacikgoz/dirty-repo.git", ""},
-		}},
+		}, AuthProtocolHTTP},
 		{&Remote{
 			URL: []string{"git@gitlab.com:isacikgoz/dirty-repo.git", ""},
-		}},
+		}, AuthProtocolSSH},
+		{&Remote{
+			URL: []string{"deploy@git.example.com:team/repo.git", ""},
+		}, AuthProtocolSSH},
+	}
+	for _, test := range tests {
+		output, err := AuthProtocol(test.input)
+		if err != nil {
+			t.Errorf("Test Failed. error: %s", err.Error())
+		}
+		if output != test.expected {
+			t.Errorf("Test Failed. %v inputted, output: %s, expected: %s", test.input.URL, output, test.expected)
+		}
+	}
+}
+
+func TestRemoteHost(t *testing.T) {
+	var tests = []struct {
+		input    *Remote
+		expected string
+	}{
+		{&Remote{URL: []string{"https://gitlab.com/isacikgoz/dirty-repo.git"}}, "gitlab.com"},
+		{&Remote{URL: []string{"http://gitlab.com:8080/isacikgoz/dirty-repo.git"}}, "gitlab.com"},
+		{&Remote{URL: []string{"ssh://git@gitlab.com/isacikgoz/dirty-repo.git"}}, "gitlab.com"},
+		{&Remote{URL: []string{"git@gitlab.com:isacikgoz/dirty-repo.git"}}, "gitlab.com"},
+		{&Remote{URL: []string{"deploy@git.example.com:team/repo.git"}}, "git.example.com"},
+		{&Remote{URL: []string{"git.example.com:repo.git"}}, "git.example.com"},
+		{&Remote{URL: []string{"./dir:with-colon/repo.git"}}, ""},
+		{&Remote{URL: []string{"/tmp/dirty-repo.git"}}, ""},
+		{&Remote{}, ""},
 	}
 	for _, test := range tests {
-		if _, err := AuthProtocol(test.input); err != nil {
+		output, err := RemoteHost(test.input)
+		if err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
+		if output != test.expected {
+			t.Errorf("Test Failed. %v inputted, output: %s, expected: %s", test.input.URL, output, test.expected)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	registered := &Credentials{Token: "registered", Bearer: true}
+	SetToken("GitHub.example.com", registered)
+	defer os.Unsetenv("GITBATCH_TOKEN_GITLAB_EXAMPLE_COM")
+	os.Setenv("GITBATCH_TOKEN_GITLAB_EXAMPLE_COM", "from-env")
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"github.example.com", "registered"},
+		{"gitlab.example.com", "from-env"},
+		{"example.com", ""},
+	}
+	for _, test := range tests {
+		output := Token(test.input)
+		if output == nil && len(test.expected) == 0 {
+			continue
+		}
+		if output == nil || output.Token != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %v, expected: %s", test.input, output, test.expected)
+		}
 	}
 }
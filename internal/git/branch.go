@@ -1,13 +1,12 @@
 package git
 
 import (
-	"fmt"
 	"os/exec"
 	"sort"
-	"strconv"
 	"strings"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
@@ -22,9 +21,15 @@ type Branch struct {
 	Upstream  *RemoteBranch
 	Commits   []*Commit
 	State     *BranchState
-	Pushables string
-	Pullables string
-	Clean     bool
+	// Pushables and Pullables are the commit counts that the branch is ahead
+	// and behind of its upstream, they are unknown if there is no upstream or
+	// the commits couldn't be counted, see Counted
+	Pushables int
+	Pullables int
+	// UpstreamGone is set if the upstream is configured but the remote branch
+	// doesn't exist anymore, e.g. it is deleted after a merge
+	UpstreamGone bool
+	Clean        bool
 }
 
 // BranchState hold the ref commit
@@ -52,18 +57,15 @@ func (r *Repository) initBranches() error {
 		return err
 	}
 	var branchFound bool
-	var push, pull string
+	clean := r.isClean()
 	bs.ForEach(func(b *plumbing.Reference) error {
 		if b.Type() != plumbing.HashReference {
 			return nil
 		}
-		clean := r.isClean()
 		branch := &Branch{
 			Name:      b.Name().Short(),
 			Reference: b,
 			State:     &BranchState{},
-			Pushables: push,
-			Pullables: pull,
 			Clean:     clean,
 		}
 		if b.Name() == headRef.Name() {
@@ -79,22 +81,85 @@ func (r *Repository) initBranches() error {
 			Name:      headRef.Hash().String(),
 			Reference: headRef,
 			State:     &BranchState{},
-			Pushables: "?",
-			Pullables: "?",
-			Clean:     r.isClean(),
+			Clean:     clean,
 		}
 		lbs = append(lbs, branch)
 		r.State.Branch = branch
 	}
-	rb, err := getUpstream(r, r.State.Branch.Name)
-	if err == nil {
-		r.State.Branch.Upstream = rb
+	cfg, err := r.Repo.Config()
+	if err != nil {
+		return err
 	}
+	for _, b := range lbs {
+		r.trackUpstream(cfg, b)
+	}
+	r.selectRemote(cfg, r.State.Branch)
 
 	r.Branches = lbs
 	return nil
 }
 
+// trackUpstream resolves the upstream of the branch from the repository
+// configuration and counts the commits the branch is ahead and behind of it.
+// If the history can't be walked, the counts of the branch are left unknown
+// rather than failing the whole repository.
+func (r *Repository) trackUpstream(cfg *config.Config, b *Branch) {
+	b.Upstream, b.UpstreamGone = r.upstream(cfg, b.Name)
+	b.Pushables, b.Pullables = 0, 0
+	if b.Upstream == nil {
+		return
+	}
+	pushables, pullables, err := AheadBehind(r, b.Reference.Hash(), b.Upstream.Reference.Hash())
+	if err != nil {
+		b.Pushables, b.Pullables = -1, -1
+		return
+	}
+	b.Pushables, b.Pullables = len(pushables), len(pullables)
+}
+
+// upstream returns the branch that is configured as the upstream of the branch,
+// gone is true if its remote exists but the branch is not found in it
+func (r *Repository) upstream(cfg *config.Config, branchName string) (rb *RemoteBranch, gone bool) {
+	bc, ok := cfg.Branches[branchName]
+	if !ok || len(bc.Remote) == 0 || len(bc.Merge) == 0 {
+		return nil, false
+	}
+	if bc.Remote == "." {
+		// the branch tracks a local branch, e.g. "git branch --track feat main"
+		ref, err := r.Repo.Reference(bc.Merge, true)
+		if err != nil {
+			return nil, false
+		}
+		return &RemoteBranch{Name: bc.Merge.Short(), Reference: ref}, false
+	}
+	name := bc.Remote + "/" + bc.Merge.Short()
+	for _, rm := range r.Remotes {
+		if rm.Name != bc.Remote {
+			continue
+		}
+		for _, rb := range rm.Branches {
+			if rb.Name == name {
+				return rb, false
+			}
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// selectRemote sets the remote of the branch's upstream as the current remote
+func (r *Repository) selectRemote(cfg *config.Config, b *Branch) {
+	bc, ok := cfg.Branches[b.Name]
+	if !ok {
+		return
+	}
+	for _, rm := range r.Remotes {
+		if rm.Name == bc.Remote {
+			r.State.Remote = rm
+		}
+	}
+}
+
 // Checkout to given branch. If any errors occur, the method returns it instead
 // of returning nil
 func (r *Repository) Checkout(b *Branch) error {
@@ -113,10 +178,11 @@ func (r *Repository) Checkout(b *Branch) error {
 	}
 	r.State.Branch = b
 
-	rb, err := getUpstream(r, r.State.Branch.Name)
-	if err == nil {
-		r.State.Branch.Upstream = rb
+	cfg, err := r.Repo.Config()
+	if err != nil {
+		return err
 	}
+	r.selectRemote(cfg, b)
 	b.initCommits(r)
 
 	if err := r.Publish(BranchUpdated, nil); err != nil {
@@ -128,32 +194,26 @@ func (r *Repository) Checkout(b *Branch) error {
 	return r.Publish(RepositoryUpdated, nil)
 }
 
-// checking the branch if it has any changes from its head revision. Initially
-// I implemented this with go-git but it was incredibly slow and there is also
-// an issue about it: https://github.com/src-d/go-git/issues/844
+// checking the worktree if it has any changes from its head revision. The
+// porcelain output is empty for a clean worktree regardless of the locale and
+// git version. Initially I implemented this with go-git but it was incredibly
+// slow and there is also an issue about it:
+// https://github.com/src-d/go-git/issues/844
+// The branches share the worktree, so it is checked once until the refresh.
 func (r *Repository) isClean() bool {
-	args := []string{"status"}
+	if r.clean != nil {
+		return *r.clean
+	}
+	args := []string{"status", "--porcelain", "--untracked-files=normal"}
 	cmd := exec.Command("git", args...)
 	cmd.Dir = r.AbsPath
-	out, err := cmd.CombinedOutput()
+	out, err := cmd.Output()
 	if err != nil {
 		return false
 	}
-	s := string(out)
-	if strings.HasSuffix(s, "\n") {
-		s = s[:len(s)-1]
-	}
-	if len(s) >= 0 {
-		vs := strings.Split(s, "\n")
-		line := vs[len(vs)-1]
-		// earlier versions of git returns "working directory clean" instead of
-		//"working tree clean" message
-		if strings.Contains(line, "working tree clean") ||
-			strings.Contains(line, "working directory clean") {
-			return true
-		}
-	}
-	return false
+	clean := len(strings.TrimSpace(string(out))) == 0
+	r.clean = &clean
+	return clean
 }
 
 // RevListOptions defines the rules of rev-list func
@@ -194,77 +254,32 @@ func RevList(r *Repository, options RevListOptions) ([]*object.Commit, error) {
 	return commits, nil
 }
 
-// SyncRemoteAndBranch synchronizes remote branch with current branch
+// SyncRemoteAndBranch synchronizes remote branch with current branch, the
+// counts are left unknown if the commits can't be counted
 func (r *Repository) SyncRemoteAndBranch(b *Branch) error {
-	headRef, err := r.Repo.Head()
+	cfg, err := r.Repo.Config()
 	if err != nil {
 		return err
 	}
-	if b.Upstream == nil {
-		b.Pullables = "?"
-		b.Pushables = "?"
-		return nil
-	}
-
-	head := headRef.Hash().String()
-	var push, pull string
-	pushables, err := RevList(r, RevListOptions{
-		Ref1: b.Upstream.Reference.Hash().String(),
-		Ref2: head,
-	})
-	if err != nil {
-		push = "?"
-	} else {
-		push = strconv.Itoa(len(pushables))
-	}
-	pullables, err := RevList(r, RevListOptions{
-		Ref1: head,
-		Ref2: b.Upstream.Reference.Hash().String(),
-	})
-	if err != nil {
-		pull = "?"
-	} else {
-		pull = strconv.Itoa(len(pullables))
-	}
-	b.Pullables = pull
-	b.Pushables = push
+	r.trackUpstream(cfg, b)
 	return nil
 }
 
-// InitializeCommits loads the commits
-func (b *Branch) InitializeCommits(r *Repository) error {
-	return b.initCommits(r)
+// Counted reports whether the commit counts of the branch to its upstream are
+// known
+func (b *Branch) Counted() bool {
+	return b.Upstream != nil && b.Pushables >= 0 && b.Pullables >= 0
 }
 
-func getUpstream(r *Repository, branchName string) (*RemoteBranch, error) {
-	args := []string{"config", "--get", "branch." + branchName + ".remote"}
-	cmd := exec.Command("git", args...)
-	cmd.Dir = r.AbsPath
-	cr, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("upstream not found")
-	}
-
-	args = []string{"config", "--get", "branch." + branchName + ".merge"}
-	cmd = exec.Command("git", args...)
-	cmd.Dir = r.AbsPath
-	cm, err := cmd.CombinedOutput()
-	if err != nil || !strings.Contains(string(cm), branchName) {
-		return nil, fmt.Errorf("default merge branch found")
-	}
-
-	for _, rm := range r.Remotes {
-		if rm.Name == strings.TrimSpace(string(cr)) {
-			r.State.Remote = rm
-		}
-	}
+// Diverged reports whether both the branch and its upstream have commits that
+// the other doesn't have, such a branch can't be fast-forwarded
+func (b *Branch) Diverged() bool {
+	return b.Upstream != nil && b.Pushables > 0 && b.Pullables > 0
+}
 
-	for _, rb := range r.State.Remote.Branches {
-		if rb.Name == r.State.Remote.Name+"/"+branchName {
-			return rb, nil
-		}
-	}
-	return nil, fmt.Errorf("upstream not found")
+// InitializeCommits loads the commits
+func (b *Branch) InitializeCommits(r *Repository) error {
+	return b.initCommits(r)
 }
 
 // trimTrailingNewline removes the trailing new line form a string. this method
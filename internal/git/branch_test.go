@@ -2,6 +2,10 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -52,6 +56,112 @@ func TestRevlistNew(t *testing.T) {
 	}
 }
 
+func TestIsClean(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clean-repo")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	steps := [][]string{
+		{"init"},
+		{"remote", "add", "origin", dir},
+		{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@example.com", "commit", "--allow-empty", "-m", "initial commit"},
+	}
+	for _, args := range steps {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Test Failed. error: %s", out)
+		}
+	}
+	r, err := InitializeRepo(dir)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if !r.State.Branch.Clean {
+		t.Errorf("Test Failed. new repository is not clean")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "file"), []byte("dirty"), 0644); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	// the cached result is kept until the refresh
+	if !r.isClean() {
+		t.Errorf("Test Failed. cleanliness is not cached")
+	}
+	if err := r.Refresh(); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if r.State.Branch.Clean {
+		t.Errorf("Test Failed. untracked file is not detected")
+	}
+}
+
+func TestTrackUpstream(t *testing.T) {
+	r, cleanup, err := testHistoryRepo(5, 2, 3)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	for _, args := range [][]string{
+		{"update-ref", "refs/remotes/origin/upstream", "upstream"},
+		{"config", "branch.master.remote", "origin"},
+		{"config", "branch.master.merge", "refs/heads/upstream"},
+		{"config", "branch.side.remote", "origin"},
+		{"config", "branch.side.merge", "refs/heads/deleted"},
+		{"branch", "feat", "base"},
+		{"config", "branch.feat.remote", "."},
+		{"config", "branch.feat.merge", "refs/heads/master"},
+		{"config", "branch.broken.remote", "origin"},
+		{"config", "branch.broken.merge", "refs/heads/upstream"},
+	} {
+		if _, err := testGit(r.AbsPath, args...); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+	}
+	// the commit of the branch is missing, so it can't be counted
+	missing := []byte("0123456789012345678901234567890123456789\n")
+	if err := ioutil.WriteFile(filepath.Join(r.AbsPath, ".git", "refs", "heads", "broken"), missing, 0644); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if err := r.Refresh(); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		branch   string
+		upstream string
+		gone     bool
+		push     int
+		pull     int
+	}{
+		{"master", "origin/upstream", false, 2, 3},
+		{"side", "", true, 0, 0},
+		{"base", "", false, 0, 0},
+		{"feat", "master", false, 0, 4},
+		{"broken", "origin/upstream", false, -1, -1},
+	}
+	for _, test := range tests {
+		var b *Branch
+		for _, lb := range r.Branches {
+			if lb.Name == test.branch {
+				b = lb
+			}
+		}
+		if b == nil {
+			t.Fatalf("Test Failed. branch %s is not found", test.branch)
+		}
+		var upstream string
+		if b.Upstream != nil {
+			upstream = b.Upstream.Name
+		}
+		if upstream != test.upstream || b.UpstreamGone != test.gone || b.Pushables != test.push || b.Pullables != test.pull {
+			t.Errorf("Test Failed. %s output: %q %t %d %d, expected: %q %t %d %d", test.branch, upstream, b.UpstreamGone, b.Pushables, b.Pullables, test.upstream, test.gone, test.push, test.pull)
+		}
+	}
+	if r.State.Branch.Name != "master" || r.State.Remote.Name != "origin" {
+		t.Errorf("Test Failed. current branch: %s, remote: %s", r.State.Branch.Name, r.State.Remote.Name)
+	}
+}
+
 func testRevRepo() (*Repository, error) {
 	return InitializeRepo("/home/isacikgoz/git-testing/gitbatch")
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -55,21 +56,23 @@ func (b *Branch) initCommits(r *Repository) error {
 		return err
 	}
 	defer cIter.Close()
-	// find commits that fetched from upstream but not merged commits
-	rmcs, _ := b.pullDiffsToUpstream(r)
-	b.Commits = append(b.Commits, rmcs...)
-
-	// find commits that not pushed to upstream
-	lcs, _ := b.pushDiffsToUpstream(r)
+	// find commits that fetched from upstream but not merged commits and the
+	// ones that not pushed to upstream
+	lcs, rmcs := b.diffsToUpstream(r)
+	for _, c := range rmcs {
+		b.Commits = append(b.Commits, commit(c, RemoteCommit))
+	}
+	locals := make(map[plumbing.Hash]bool)
+	for _, c := range lcs {
+		locals[c.Hash] = true
+	}
 
 	// ... just iterates over the commits
 	err = cIter.ForEach(func(c *object.Commit) error {
 
 		cmType := EvenCommit
-		for _, lc := range lcs {
-			if lc.Hash == c.Hash.String() {
-				cmType = LocalCommit
-			}
+		if locals[c.Hash] {
+			cmType = LocalCommit
 		}
 
 		commit := commit(c, cmType)
@@ -85,53 +88,18 @@ func (b *Branch) initCommits(r *Repository) error {
 	return nil
 }
 
-// this function creates the commit entities according to active branchs diffs
-// to *its* configured upstream
-func (b *Branch) pullDiffsToUpstream(r *Repository) ([]*Commit, error) {
-	remoteCommits := make([]*Commit, 0)
-	if r.State.Branch.Upstream == nil {
-		return remoteCommits, nil
-	}
-	head := b.Reference.Hash().String()
-
-	pullables, err := RevList(r, RevListOptions{
-		Ref1: head,
-		Ref2: b.Upstream.Reference.Hash().String(),
-	})
-	if err != nil {
-		// possibly found nothing or no upstream set
-	} else {
-		for _, c := range pullables {
-			commit := commit(c, RemoteCommit)
-			remoteCommits = append(remoteCommits, commit)
-		}
-	}
-	return remoteCommits, nil
-}
-
-// this function returns the hashes of the commits that are not pushed to the
-// upstream of the specific branch
-func (b *Branch) pushDiffsToUpstream(r *Repository) ([]*Commit, error) {
-	notPushedCommits := make([]*Commit, 0)
-	if r.State.Branch.Upstream == nil {
-		return notPushedCommits, nil
+// this function returns the commits that are not pushed to and not merged from
+// the upstream of the specific branch
+func (b *Branch) diffsToUpstream(r *Repository) ([]*object.Commit, []*object.Commit) {
+	if b.Upstream == nil {
+		return nil, nil
 	}
-	head := b.Reference.Hash().String()
-
-	pushables, err := RevList(r, RevListOptions{
-		Ref1: b.Upstream.Reference.Hash().String(),
-		Ref2: head,
-	})
-
+	ahead, behind, err := AheadBehind(r, b.Reference.Hash(), b.Upstream.Reference.Hash())
 	if err != nil {
 		// possibly found nothing or no upstream set
-	} else {
-		for _, c := range pushables {
-			commit := commit(c, LocalCommit)
-			notPushedCommits = append(notPushedCommits, commit)
-		}
+		return nil, nil
 	}
-	return notPushedCommits, nil
+	return ahead, behind
 }
 
 func commit(c *object.Commit, t CommitType) *Commit {
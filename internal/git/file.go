@@ -10,6 +10,8 @@ type File struct {
 	AbsPath string
 	X       FileStatus
 	Y       FileStatus
+	// OrigName is the path of the file before it is renamed or copied
+	OrigName string
 }
 
 // FileStatus is the short representation of state of a file
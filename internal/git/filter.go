@@ -0,0 +1,113 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter selects repositories with a query. The query consists of space
+// separated terms and a repository has to satisfy all of them. A term is either
+// a predicate or a text that is fuzzy matched against the name, branch name or
+// path of the repository. Supported predicates are dirty, clean, no-upstream,
+// diverged, failed, conflicted and comparisons of ahead/behind counts such as
+// behind>0 or ahead=2.
+type Filter struct {
+	Query string
+	terms []func(r *Repository) bool
+}
+
+// NewFilter parses the query and returns a filter for it
+func NewFilter(query string) *Filter {
+	f := &Filter{Query: query}
+	for _, t := range strings.Fields(query) {
+		f.terms = append(f.terms, parseTerm(t))
+	}
+	return f
+}
+
+// Match reports whether the repository satisfies every term of the filter
+func (f *Filter) Match(r *Repository) bool {
+	for _, term := range f.terms {
+		if !term(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseTerm(t string) func(r *Repository) bool {
+	switch t {
+	case "dirty":
+		return func(r *Repository) bool { return r.State.Branch != nil && !r.State.Branch.Clean }
+	case "clean":
+		return func(r *Repository) bool { return r.State.Branch != nil && r.State.Branch.Clean }
+	case "no-upstream":
+		return func(r *Repository) bool { return r.State.Branch != nil && r.State.Branch.Upstream == nil }
+	case "diverged":
+		return func(r *Repository) bool { return r.State.Branch != nil && r.State.Branch.Diverged() }
+	case "failed":
+		return func(r *Repository) bool { return r.WorkStatus() == Fail }
+	case "conflicted":
+		return func(r *Repository) bool { return r.WorkStatus() == Conflicted }
+	}
+	if i := strings.IndexAny(t, "<>="); i > 0 {
+		if n, err := strconv.Atoi(t[i+1:]); err == nil {
+			if cmp := parseComparison(t[:i], t[i], n); cmp != nil {
+				return cmp
+			}
+		}
+	}
+	return func(r *Repository) bool {
+		if fuzzyMatch(t, r.Name) || fuzzyMatch(t, r.AbsPath) {
+			return true
+		}
+		return r.State.Branch != nil && fuzzyMatch(t, r.State.Branch.Name)
+	}
+}
+
+// parseComparison returns a predicate comparing the ahead or behind count of
+// the branch, nil if the field is unknown
+func parseComparison(field string, op byte, n int) func(r *Repository) bool {
+	var count func(b *Branch) int
+	switch field {
+	case "ahead":
+		count = func(b *Branch) int { return b.Pushables }
+	case "behind":
+		count = func(b *Branch) int { return b.Pullables }
+	default:
+		return nil
+	}
+	return func(r *Repository) bool {
+		if r.State.Branch == nil || !r.State.Branch.Counted() {
+			return false
+		}
+		c := count(r.State.Branch)
+		switch op {
+		case '<':
+			return c < n
+		case '>':
+			return c > n
+		}
+		return c == n
+	}
+}
+
+// fuzzyMatch reports whether the runes of the pattern appear in the text in the
+// same order, case insensitively
+func fuzzyMatch(pattern, text string) bool {
+	pr := []rune(pattern)
+	if len(pr) == 0 {
+		return true
+	}
+	i := 0
+	for _, c := range text {
+		if unicode.ToLower(c) == unicode.ToLower(pr[i]) {
+			i++
+			if i == len(pr) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	newRepository := func(name, branch string, push, pull int, clean, upstream bool, ws WorkStatus) *Repository {
+		b := &Branch{Name: branch, Pushables: push, Pullables: pull, Clean: clean}
+		if upstream {
+			b.Upstream = &RemoteBranch{Name: "origin/" + branch}
+		}
+		return &Repository{
+			Name:    name,
+			AbsPath: "/src/" + name,
+			State:   &RepositoryState{Branch: b, workStatus: ws},
+		}
+	}
+	api := newRepository("api-server", "master", 0, 3, true, true, Available)
+	web := newRepository("dashboard", "feature/login", 2, 0, false, true, Fail)
+	cli := newRepository("gitbatch", "develop", 0, 0, true, false, Success)
+	lib := newRepository("mobile", "release", 1, 2, true, true, Skipped)
+
+	var tests = []struct {
+		input    string
+		expected []*Repository
+	}{
+		{"", []*Repository{api, web, cli, lib}},
+		{"apsrv", []*Repository{api}},
+		{"diverged", []*Repository{lib}},
+		{"LOGIN", []*Repository{web}},
+		{"src/git", []*Repository{cli}},
+		{"dirty", []*Repository{web}},
+		{"clean", []*Repository{api, cli, lib}},
+		{"behind>0", []*Repository{api, lib}},
+		{"ahead>0", []*Repository{web, lib}},
+		{"ahead=0", []*Repository{api}},
+		{"behind<1", []*Repository{web}},
+		{"no-upstream", []*Repository{cli}},
+		{"failed", []*Repository{web}},
+		{"conflicted", []*Repository{}},
+		{"clean a", []*Repository{api, cli, lib}},
+		{"clean ahead>0", []*Repository{lib}},
+		{"diverged ahead=2", []*Repository{}},
+	}
+	for _, test := range tests {
+		f := NewFilter(test.input)
+		output := make([]*Repository, 0)
+		for _, r := range []*Repository{api, web, cli, lib} {
+			if f.Match(r) {
+				output = append(output, r)
+			}
+		}
+		if len(output) != len(test.expected) {
+			t.Errorf("Test Failed. %s inputted, output: %v, expected: %v", test.input, output, test.expected)
+			continue
+		}
+		for i := range output {
+			if output[i] != test.expected[i] {
+				t.Errorf("Test Failed. %s inputted, output: %v, expected: %v", test.input, output, test.expected)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -28,8 +29,14 @@ type Repository struct {
 	Stasheds []*StashedItem
 	State    *RepositoryState
 
+	// clean is the cached cleanliness of the worktree, nil until it is
+	// checked after the last refresh
+	clean     *bool
 	mutex     *sync.RWMutex
 	listeners map[string][]RepositoryListener
+	// work serializes the operations that change the repository, e.g. a job
+	// and a refresh after a change on the disk
+	work sync.Mutex
 }
 
 // RepositoryState is the current pointers of a repository
@@ -38,6 +45,12 @@ type RepositoryState struct {
 	Branch     *Branch
 	Remote     *Remote
 	Message    string
+	// LastFetch is the time of the last successful fetch, zero if the
+	// repository hasn't been fetched yet
+	LastFetch time.Time
+	// LastError is the error of the last failed job, nil if the last job
+	// succeeded
+	LastError error
 }
 
 // RepositoryListener is a type for listeners
@@ -69,6 +82,14 @@ var (
 	Success = WorkStatus{Status: 4, Ready: true}
 	// Fail is the unexpected outcome of the operation
 	Fail = WorkStatus{Status: 5, Ready: false}
+	// Cancelled means the operation is stopped by the user or timed out
+	Cancelled = WorkStatus{Status: 6, Ready: true}
+	// Conflicted means the operation stopped with conflicts, it has to be
+	// continued or aborted
+	Conflicted = WorkStatus{Status: 7, Ready: false}
+	// Skipped means the operation is not applied since the repository is not
+	// in a suitable state for it, e.g. a diverged branch can't be fast-forwarded
+	Skipped = WorkStatus{Status: 8, Ready: true}
 )
 
 const (
@@ -113,10 +134,36 @@ func InitializeRepo(dir string) (r *Repository, err error) {
 	if err != nil {
 		return nil, err
 	}
+	// a rebase or merge may be left stopped on a conflict by a previous run
+	if r.Rebasing() {
+		r.State.workStatus = Conflicted
+		r.State.Message = "rebase in progress"
+	} else if r.Merging() {
+		r.State.workStatus = Conflicted
+		r.State.Message = "merge in progress"
+	}
 	// need nothing extra but loading additional components
 	return r, r.loadComponents(true)
 }
 
+// Rebasing reports whether a rebase of the repository is stopped and waiting
+// to be continued or aborted
+func (r *Repository) Rebasing() bool {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(r.AbsPath, ".git", dir)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Merging reports whether a merge of the repository is stopped and waiting to
+// be concluded or aborted
+func (r *Repository) Merging() bool {
+	_, err := os.Stat(filepath.Join(r.AbsPath, ".git", "MERGE_HEAD"))
+	return err == nil
+}
+
 // loadComponents initializes the fields of a repository such as branches,
 // remotes, commits etc. If reset, reload commit, remote pointers too
 func (r *Repository) loadComponents(reset bool) error {
@@ -124,13 +171,10 @@ func (r *Repository) loadComponents(reset bool) error {
 		return err
 	}
 
+	// the branches are loaded along with their ahead and behind counts
 	if err := r.initBranches(); err != nil {
 		return err
 	}
-
-	if err := r.SyncRemoteAndBranch(r.State.Branch); err != nil {
-		return err
-	}
 	r.loadStashedItems()
 
 	return nil
@@ -156,6 +200,8 @@ func (r *Repository) Refresh() error {
 	r.Repo = *rp
 	// modification date may be changed
 	r.ModTime = fstat.ModTime()
+	// the worktree may be changed by the operation
+	r.clean = nil
 	if err := r.loadComponents(false); err != nil {
 		return err
 	}
@@ -163,6 +209,17 @@ func (r *Repository) Refresh() error {
 	return r.Publish(RepositoryUpdated, nil)
 }
 
+// Lock waits for the running operation on the repository to finish and holds
+// the repository until Unlock is called
+func (r *Repository) Lock() {
+	r.work.Lock()
+}
+
+// Unlock releases the repository for the other operations
+func (r *Repository) Unlock() {
+	r.work.Unlock()
+}
+
 // On adds new listener.
 // listener is a callback function that will be called when event emits
 func (r *Repository) On(event string, listener RepositoryListener) {
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// the sides of the walk a commit is reachable from
+const (
+	leftSide uint8 = 1 << iota
+	rightSide
+	// stale commits are reachable from both sides, so are their parents
+	staleSide
+)
+
+// AheadBehind returns the commits that are reachable from the local commit but
+// not from the upstream one and vice versa, like the "git rev-list --left-right
+// local...upstream" does. The commit graph is walked natively from the newest
+// commits to the older ones and it stops as soon as the rest of the history is
+// common to both sides, so that the cost doesn't depend on the history size.
+func AheadBehind(r *Repository, local, upstream plumbing.Hash) (ahead, behind []*object.Commit, err error) {
+	ahead = make([]*object.Commit, 0)
+	behind = make([]*object.Commit, 0)
+	if local == upstream {
+		return ahead, behind, nil
+	}
+	flags := make(map[plumbing.Hash]uint8)
+	commits := make(map[plumbing.Hash]*object.Commit)
+	queued := make(map[plumbing.Hash]bool)
+	q := &commitQueue{}
+	// the walk is over once the queued commits are reachable from both sides,
+	// so the number of the non-stale ones in the queue is kept
+	nonStale := 0
+	push := func(c *object.Commit, f uint8) {
+		pf := flags[c.Hash]
+		flags[c.Hash] = pf | f
+		if queued[c.Hash] {
+			if pf&staleSide == 0 && f&staleSide != 0 {
+				nonStale--
+			}
+			return
+		}
+		if (pf|f)&staleSide == 0 {
+			nonStale++
+		}
+		queued[c.Hash] = true
+		commits[c.Hash] = c
+		heap.Push(q, c)
+	}
+	for _, tip := range []struct {
+		hash plumbing.Hash
+		side uint8
+	}{
+		{local, leftSide},
+		{upstream, rightSide},
+	} {
+		c, err := r.Repo.CommitObject(tip.hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		push(c, tip.side)
+	}
+	for nonStale > 0 {
+		c := heap.Pop(q).(*object.Commit)
+		delete(queued, c.Hash)
+		f := flags[c.Hash]
+		if f&staleSide == 0 {
+			nonStale--
+		}
+		if f&(leftSide|rightSide) == leftSide|rightSide {
+			f |= staleSide
+			flags[c.Hash] = f
+		}
+		for _, h := range c.ParentHashes {
+			if pf := flags[h]; pf|f == pf {
+				continue
+			}
+			p, ok := commits[h]
+			if !ok {
+				p, err = r.Repo.CommitObject(h)
+				if err == plumbing.ErrObjectNotFound {
+					// the history of a shallow clone is cut
+					continue
+				} else if err != nil {
+					return nil, nil, err
+				}
+			}
+			push(p, f)
+		}
+	}
+	for h, f := range flags {
+		switch f & (leftSide | rightSide) {
+		case leftSide:
+			ahead = append(ahead, commits[h])
+		case rightSide:
+			behind = append(behind, commits[h])
+		}
+	}
+	sort.Sort(CommitTime(ahead))
+	sort.Sort(CommitTime(behind))
+	return ahead, behind, nil
+}
+
+// commitQueue is a priority queue of the commits, the newest is the first
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *commitQueue) Push(x interface{}) { *q = append(*q, x.(*object.Commit)) }
+
+func (q *commitQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,191 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestAheadBehind(t *testing.T) {
+	// the common history has a merge, local and upstream diverge after it
+	r, cleanup, err := testHistoryRepo(50, 3, 5)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	var tests = []struct {
+		local    string
+		upstream string
+	}{
+		{"master", "upstream"},
+		{"upstream", "master"},
+		{"master", "master"},
+		{"base", "master"},
+		{"upstream", "base"},
+	}
+	for _, test := range tests {
+		out, err := testGit(r.AbsPath, "rev-list", "--left-right", "--count", test.local+"..."+test.upstream)
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		ahead, behind, err := AheadBehind(r, testHash(r, test.local), testHash(r, test.upstream))
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		if output := fmt.Sprintf("%d\t%d", len(ahead), len(behind)); output != strings.TrimSpace(out) {
+			t.Errorf("Test Failed. %s...%s output: %s, expected: %s", test.local, test.upstream, output, out)
+		}
+	}
+}
+
+func TestAheadBehindShallow(t *testing.T) {
+	r, cleanup, err := testHistoryRepo(10, 2, 3)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	dir, err := ioutil.TempDir("", "shallow-repo")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	// the parents of the tips are missing in the clone
+	for _, args := range [][]string{
+		{"clone", "-q", "--depth", "1", "--no-single-branch", "file://" + r.AbsPath, dir},
+		{"-C", dir, "branch", "-q", "-u", "origin/upstream", "master"},
+	} {
+		if _, err := testGit(r.AbsPath, args...); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+	}
+	shallow, err := InitializeRepo(dir)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	out, err := testGit(dir, "rev-list", "--left-right", "--count", "master...origin/upstream")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	b := shallow.State.Branch
+	if output := fmt.Sprintf("%d\t%d", b.Pushables, b.Pullables); output != strings.TrimSpace(out) {
+		t.Errorf("Test Failed. output: %s, expected: %s", output, out)
+	}
+}
+
+func BenchmarkAheadBehind(b *testing.B) {
+	r, cleanup, err := testHistoryRepo(20000, 20, 30)
+	if err != nil {
+		b.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	local, upstream := testHash(r, "master"), testHash(r, "upstream")
+	b.Run("native", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := AheadBehind(r, local, upstream); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("rev-list", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, opt := range []RevListOptions{
+				{Ref1: upstream.String(), Ref2: local.String()},
+				{Ref1: local.String(), Ref2: upstream.String()},
+			} {
+				if _, err := RevList(r, opt); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// testHistoryRepo creates a repository with a linear history of the given size
+// on the "base" branch and a merge on top of it, master and upstream has the
+// given number of commits after it
+func testHistoryRepo(size, ahead, behind int) (*Repository, func(), error) {
+	dir, err := ioutil.TempDir("", "history-repo")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	var stream bytes.Buffer
+	mark := 0
+	commit := func(branch string, from int, merge int) int {
+		mark++
+		fmt.Fprintf(&stream, "commit refs/heads/%s\nmark :%d\n", branch, mark)
+		fmt.Fprintf(&stream, "committer gitbatch <gitbatch@example.com> %d +0000\n", 1500000000+mark)
+		fmt.Fprintf(&stream, "data 10\ncommit %03d\n", mark%1000)
+		if from > 0 {
+			fmt.Fprintf(&stream, "from :%d\n", from)
+		}
+		if merge > 0 {
+			fmt.Fprintf(&stream, "merge :%d\n", merge)
+		}
+		fmt.Fprintf(&stream, "M 644 inline file\ndata %d\n%d\n", len(fmt.Sprint(mark))+1, mark)
+		return mark
+	}
+	head := 0
+	for i := 0; i < size; i++ {
+		head = commit("base", head, 0)
+	}
+	side := commit("side", head, 0)
+	head = commit("master", head, side)
+	local, upstream := head, head
+	for i := 0; i < ahead; i++ {
+		local = commit("master", local, 0)
+	}
+	for i := 0; i < behind; i++ {
+		upstream = commit("upstream", upstream, 0)
+	}
+	if _, err := testGit(dir, "init"); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = dir
+	cmd.Stdin = &stream
+	if out, err := cmd.CombinedOutput(); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("%s: %s", err, out)
+	}
+	for _, args := range [][]string{
+		{"checkout", "-q", "-f", "master"},
+		{"remote", "add", "origin", dir},
+	} {
+		if _, err := testGit(dir, args...); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+	r, err := InitializeRepo(dir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return r, cleanup, nil
+}
+
+func testGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", err, out)
+	}
+	return string(out), nil
+}
+
+func testHash(r *Repository, branch string) plumbing.Hash {
+	ref, err := r.Repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	return ref.Hash()
+}
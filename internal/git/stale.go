@@ -0,0 +1,114 @@
+package git
+
+import (
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// defaultBase is the base branch of the cleanup if the remote has no HEAD
+const defaultBase = "master"
+
+// StaleBranch is a local branch that is a candidate to be cleaned up
+type StaleBranch struct {
+	Branch *Branch
+	// Merged is set if all the commits of the branch are reachable from the
+	// base, such a branch can be deleted without losing any work
+	Merged bool
+	// Gone is set if the upstream of the branch is deleted from the remote
+	Gone bool
+}
+
+// StaleBranches returns the local branches that are merged into the base or
+// whose upstream is gone. The base is a local branch or a branch of the current
+// remote, the default branch of the remote is used if it is empty. The current
+// branch and the base itself are never listed.
+func (r *Repository) StaleBranches(base string) ([]*StaleBranch, error) {
+	name, hash, found := r.resolveBase(base)
+	tips := make(map[plumbing.Hash]bool)
+	for _, b := range r.Branches {
+		if b == r.State.Branch || b.Name == name {
+			continue
+		}
+		tips[b.Reference.Hash()] = false
+	}
+	if found && len(tips) > 0 {
+		if err := r.reachable(hash, tips); err != nil {
+			return nil, err
+		}
+	}
+	stale := make([]*StaleBranch, 0)
+	for _, b := range r.Branches {
+		merged, ok := tips[b.Reference.Hash()]
+		if !ok || b == r.State.Branch || b.Name == name {
+			continue
+		}
+		if merged || b.UpstreamGone {
+			stale = append(stale, &StaleBranch{
+				Branch: b,
+				Merged: merged,
+				Gone:   b.UpstreamGone,
+			})
+		}
+	}
+	return stale, nil
+}
+
+// resolveBase returns the short name and the commit of the base branch, the
+// local branch is preferred over the remote one
+func (r *Repository) resolveBase(base string) (string, plumbing.Hash, bool) {
+	if len(base) == 0 {
+		base = r.remoteHead()
+	}
+	names := []plumbing.ReferenceName{plumbing.NewBranchReferenceName(base)}
+	if r.State.Remote != nil {
+		names = append(names, plumbing.NewRemoteReferenceName(r.State.Remote.Name, base))
+	}
+	for _, n := range names {
+		if ref, err := r.Repo.Reference(n, true); err == nil {
+			return base, ref.Hash(), true
+		}
+	}
+	return base, plumbing.ZeroHash, false
+}
+
+// remoteHead returns the name of the default branch of the current remote
+func (r *Repository) remoteHead() string {
+	if r.State.Remote == nil {
+		return defaultBase
+	}
+	ref, err := r.Repo.Reference(plumbing.NewRemoteReferenceName(r.State.Remote.Name, "HEAD"), false)
+	if err != nil || ref.Type() != plumbing.SymbolicReference {
+		return defaultBase
+	}
+	return strings.TrimPrefix(ref.Target().Short(), r.State.Remote.Name+"/")
+}
+
+// reachable walks the history of the commit once and marks the tips that are
+// found in it, the walk stops as soon as all of them are found
+func (r *Repository) reachable(from plumbing.Hash, tips map[plumbing.Hash]bool) error {
+	left := len(tips)
+	iter, err := r.Repo.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	err = iter.ForEach(func(c *object.Commit) error {
+		if found, ok := tips[c.Hash]; ok && !found {
+			tips[c.Hash] = true
+			left--
+		}
+		if left == 0 {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err == plumbing.ErrObjectNotFound {
+		// the history of a shallow clone is cut
+		return nil
+	}
+	return err
+}
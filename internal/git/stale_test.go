@@ -0,0 +1,58 @@
+package git
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestStaleBranches(t *testing.T) {
+	r, cleanup, err := testHistoryRepo(5, 2, 3)
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	for _, args := range [][]string{
+		{"branch", "wip", "master"},
+		{"config", "branch.side.remote", "origin"},
+		{"config", "branch.side.merge", "refs/heads/side"},
+		{"config", "branch.wip.remote", "origin"},
+		{"config", "branch.wip.merge", "refs/heads/wip"},
+	} {
+		if _, err := testGit(r.AbsPath, args...); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+	}
+	if err := r.Refresh(); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	var tests = []struct {
+		base     string
+		expected string
+	}{
+		{"upstream", "base:merged side:merged,gone wip:gone"},
+		{"", "base:merged side:merged,gone wip:merged,gone"},
+		{"missing", "side:gone wip:gone"},
+	}
+	for _, test := range tests {
+		stale, err := r.StaleBranches(test.base)
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		output := make([]string, 0)
+		for _, s := range stale {
+			reasons := make([]string, 0)
+			if s.Merged {
+				reasons = append(reasons, "merged")
+			}
+			if s.Gone {
+				reasons = append(reasons, "gone")
+			}
+			output = append(output, s.Branch.Name+":"+strings.Join(reasons, ","))
+		}
+		sort.Strings(output)
+		if strings.Join(output, " ") != test.expected {
+			t.Errorf("Test Failed. base: %q, output: %s, expected: %s", test.base, output, test.expected)
+		}
+	}
+}
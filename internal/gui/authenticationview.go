@@ -19,6 +19,10 @@ var (
 	authUserLabelFeature      = viewFeature{Name: "authuserlabel", Title: " User: "}
 	authPswdLabelViewFeature  = viewFeature{Name: "authpasswdlabel", Title: " Password: "}
 
+	// ssh remotes are authenticated with a private key instead of a user
+	authKeyLabel        = " Key: "
+	authPassphraseLabel = " Passphrase: "
+
 	// these views used as a input for the credentials
 	authUserFeature         = viewFeature{Name: "authuser", Title: " User "}
 	authPasswordViewFeature = viewFeature{Name: "authpasswd", Title: " Password "}
@@ -96,28 +100,58 @@ func (gui *Gui) submitAuthenticationView(g *gocui.Gui, v *gocui.View) error {
 	creduser := re.ReplaceAllString(vUser.ViewBuffer(), "")
 	credpswd := re.ReplaceAllString(vPswd.ViewBuffer(), "")
 
+	credentials := &git.Credentials{
+		User:     creduser,
+		Password: credpswd,
+	}
+	if requiresKey(jobRequiresAuth) {
+		credentials = &git.Credentials{
+			PrivateKey: creduser,
+			Passphrase: credpswd,
+		}
+	}
+
 	// since the git ops require different types of options we better switch
 	switch mode := jobRequiresAuth.JobType; mode {
 	case job.FetchJob:
 		jobRequiresAuth.Options = &command.FetchOptions{
-			RemoteName: jobRequiresAuth.Repository.State.Remote.Name,
-			Credentials: &git.Credentials{
-				User:     creduser,
-				Password: credpswd,
-			},
+			RemoteName:  jobRequiresAuth.Repository.State.Remote.Name,
+			CommandMode: command.ModeNative,
+			Credentials: credentials,
 		}
 	case job.PullJob:
 		// we handle pull as fetch&merge so same rule applies
-		jobRequiresAuth.Options = &command.PullOptions{
-			RemoteName: jobRequiresAuth.Repository.State.Remote.Name,
-			Credentials: &git.Credentials{
-				User:     creduser,
-				Password: credpswd,
-			},
+		opts := &command.PullOptions{
+			RemoteName:  jobRequiresAuth.Repository.State.Remote.Name,
+			CommandMode: command.ModeNative,
+			Credentials: credentials,
+		}
+		// the credentials are used by the native commands only
+		if prev, ok := jobRequiresAuth.Options.(*command.PullOptions); ok {
+			opts.Rebase, opts.AutoStash, opts.FFOnly = prev.Rebase, prev.AutoStash, prev.FFOnly
+		}
+		jobRequiresAuth.Options = opts
+	case job.PushJob:
+		jobRequiresAuth.Options = &command.PushOptions{
+			RemoteName:  jobRequiresAuth.Repository.State.Remote.Name,
+			SetUpstream: jobRequiresAuth.Repository.State.Branch.Upstream == nil,
+			CommandMode: command.ModeNative,
+			Credentials: credentials,
 		}
 	}
 	jobRequiresAuth.Repository.SetWorkStatus(git.Queued)
 
+	// the other repositories on the same host are waiting for the same
+	// credentials, they are queued again and use the remembered ones
+	job.SetCredentials(jobRequiresAuth.Repository, credentials)
+	for _, j := range gui.State.FailoverQueue.Jobs() {
+		if j.Repository.WorkStatus() == git.Paused && job.SameHost(j.Repository, jobRequiresAuth.Repository) {
+			gui.State.FailoverQueue.RemoveFromQueue(j.Repository)
+			j.Repository.SetWorkStatus(git.Queued)
+			gui.State.Queue.AddJob(j)
+		}
+	}
+
 	// add this job to the last of the queue
 	if err := gui.State.Queue.AddJob(jobRequiresAuth); err != nil {
 		return err
@@ -135,7 +169,11 @@ func (gui *Gui) openUserView(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		fmt.Fprintln(vlabel, authUserLabelFeature.Title)
+		if requiresKey(jobRequiresAuth) {
+			fmt.Fprintln(vlabel, authKeyLabel)
+		} else {
+			fmt.Fprintln(vlabel, authUserLabelFeature.Title)
+		}
 		vlabel.Frame = false
 	}
 	// second, crete the user input
@@ -147,6 +185,12 @@ func (gui *Gui) openUserView(g *gocui.Gui) error {
 		v.Title = authUserFeature.Title
 		v.Editable = true
 		v.Frame = false
+		// suggest the default key, it is the one that requires a passphrase
+		if requiresKey(jobRequiresAuth) {
+			key := git.DefaultPrivateKey()
+			fmt.Fprint(v, key)
+			v.SetCursor(len(key), 0)
+		}
 	}
 	return gui.focusToView(authUserFeature.Name)
 }
@@ -160,7 +204,11 @@ func (gui *Gui) openPasswordView(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		fmt.Fprintln(vlabel, authPswdLabelViewFeature.Title)
+		if requiresKey(jobRequiresAuth) {
+			fmt.Fprintln(vlabel, authPassphraseLabel)
+		} else {
+			fmt.Fprintln(vlabel, authPswdLabelViewFeature.Title)
+		}
 		vlabel.Frame = false
 	}
 	// second, crete the masked password input
@@ -182,3 +230,10 @@ func (gui *Gui) nextAuthView(g *gocui.Gui, v *gocui.View) error {
 	err := gui.nextViewOfGroup(g, v, authViews)
 	return err
 }
+
+// ssh remotes require a private key and its passphrase rather than a user and
+// password
+func requiresKey(j *job.Job) bool {
+	protocol, err := git.AuthProtocol(j.Repository.State.Remote)
+	return err == nil && protocol == git.AuthProtocolSSH
+}
@@ -0,0 +1,202 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/job"
+	"github.com/jroimartin/gocui"
+)
+
+// cleanupState holds the stale branches of the repositories and the selection
+// of the cleanup view
+type cleanupState struct {
+	candidates []*cleanupCandidate
+	index      int
+}
+
+// cleanupCandidate is a stale branch and whether it is selected to be deleted
+type cleanupCandidate struct {
+	repository *git.Repository
+	branch     *git.StaleBranch
+	selected   bool
+}
+
+// open the review of the stale branches of the visible repositories, the
+// merged ones are selected initially
+func (gui *Gui) openCleanupView(g *gocui.Gui, v *gocui.View) error {
+	candidates := make([]*cleanupCandidate, 0)
+	for _, r := range gui.visibleRepositories() {
+		// the branches of a repository in the queue may be changed by its job
+		if ws := r.WorkStatus(); ws == git.Queued || ws == git.Working || r.State.Branch == nil {
+			continue
+		}
+		stale, err := r.StaleBranches(gui.State.cleanupBase)
+		if err != nil {
+			continue
+		}
+		for _, s := range stale {
+			candidates = append(candidates, &cleanupCandidate{
+				repository: r,
+				branch:     s,
+				selected:   s.Merged,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		return gui.openErrorView(g, "there is no stale branch", "the branches merged into "+gui.cleanupBaseLabel()+" or whose upstream is pruned are listed", mainViewFeature.Name)
+	}
+	gui.State.cleanup = cleanupState{candidates: candidates}
+	maxX, maxY := g.Size()
+	cv, err := g.SetView(cleanupViewFeature.Name, 2, 1, maxX-3, maxY-3)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		cv.Title = cleanupViewFeature.Title
+		cv.Wrap = false
+		cv.Autoscroll = false
+	}
+	if err := gui.renderCleanup(); err != nil {
+		return err
+	}
+	return gui.focusToView(cleanupViewFeature.Name)
+}
+
+// the name of the base branch that is shown to the user
+func (gui *Gui) cleanupBaseLabel() string {
+	if len(gui.State.cleanupBase) == 0 {
+		return "the default branch"
+	}
+	return gui.State.cleanupBase
+}
+
+// move the cursor to the next branch
+func (gui *Gui) cleanupCursorDown(g *gocui.Gui, v *gocui.View) error {
+	if cs := &gui.State.cleanup; cs.index < len(cs.candidates)-1 {
+		cs.index++
+	}
+	return gui.renderCleanup()
+}
+
+// move the cursor to the previous branch
+func (gui *Gui) cleanupCursorUp(g *gocui.Gui, v *gocui.View) error {
+	if cs := &gui.State.cleanup; cs.index > 0 {
+		cs.index--
+	}
+	return gui.renderCleanup()
+}
+
+// select or deselect the branch under the cursor, the unmerged branches can't
+// be selected since their work would be lost
+func (gui *Gui) toggleCleanupBranch(g *gocui.Gui, v *gocui.View) error {
+	cs := &gui.State.cleanup
+	if cs.index < len(cs.candidates) {
+		if c := cs.candidates[cs.index]; c.branch.Merged {
+			c.selected = !c.selected
+		}
+	}
+	return gui.renderCleanup()
+}
+
+// queue a job for every repository to delete its selected branches and start
+// them
+func (gui *Gui) confirmCleanup(g *gocui.Gui, v *gocui.View) error {
+	jobs := make([]*job.Job, 0)
+	byRepository := make(map[*git.Repository]*job.CleanupOptions)
+	for _, c := range gui.State.cleanup.candidates {
+		if !c.selected {
+			continue
+		}
+		opts, ok := byRepository[c.repository]
+		if !ok {
+			opts = &job.CleanupOptions{Base: gui.State.cleanupBase}
+			byRepository[c.repository] = opts
+			jobs = append(jobs, &job.Job{
+				JobType:    job.CleanupJob,
+				Repository: c.repository,
+				Options:    opts,
+				Timeout:    gui.State.jobTimeout,
+			})
+		}
+		opts.Branches = append(opts.Branches, c.branch.Branch.Name)
+	}
+	for _, j := range jobs {
+		if err := gui.State.Queue.AddJob(j); err == nil {
+			j.Repository.SetWorkStatus(git.Queued)
+		}
+	}
+	if err := gui.closeCleanupView(g, v); err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	return gui.startQueue(g, v)
+}
+
+// close the cleanup view without deleting any branch
+func (gui *Gui) closeCleanupView(g *gocui.Gui, v *gocui.View) error {
+	gui.State.cleanup = cleanupState{}
+	if err := g.DeleteView(cleanupViewFeature.Name); err != nil {
+		return nil
+	}
+	return gui.closeViewCleanup(mainViewFeature.Name)
+}
+
+// renders the stale branches grouped by their repositories
+func (gui *Gui) renderCleanup() error {
+	v, err := gui.g.View(cleanupViewFeature.Name)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	cs := &gui.State.cleanup
+	fmt.Fprintf(v, "%sBranches merged into %s or whose upstream is gone (space: select, enter: delete)\n", tab, gui.cleanupBaseLabel())
+	lines, si := 1, 0
+	// the names are aligned so that the reasons are in the same column
+	width := 0
+	for _, c := range cs.candidates {
+		if len(c.branch.Branch.Name) > width {
+			width = len(c.branch.Branch.Name)
+		}
+	}
+	var last *git.Repository
+	for i, c := range cs.candidates {
+		if c.repository != last {
+			last = c.repository
+			fmt.Fprintf(v, "\n%s%s\n", tab, cyan.Sprint(c.repository.Name))
+			lines += 2
+		}
+		line := renderStaleBranch(c, width)
+		if i == cs.index {
+			si = lines
+			fmt.Fprintf(v, "%s%s\n", tab, green.Sprint(line))
+		} else {
+			fmt.Fprintf(v, "%s%s%s\n", tab, tab, line)
+		}
+		lines++
+	}
+	return adjustAnchor(si, lines, v)
+}
+
+// renderStaleBranch returns the selection, name and the reasons of a stale
+// branch
+func renderStaleBranch(c *cleanupCandidate, width int) string {
+	check := "[ ]"
+	if c.selected {
+		check = "[x]"
+	}
+	reasons := make([]string, 0)
+	if c.branch.Merged {
+		reasons = append(reasons, "merged")
+	} else {
+		check = "[-]"
+		reasons = append(reasons, red.Sprint("unmerged"))
+	}
+	if c.branch.Gone {
+		reasons = append(reasons, yellow.Sprint("gone"))
+	}
+	return check + ws + align(c.branch.Branch.Name, width, true, false) + sep + strings.Join(reasons, ", ")
+}
@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/jroimartin/gocui"
+)
+
+// list the unmerged files of the stopped merge or rebase
+func (gui *Gui) initFocusConflicts(r *git.Repository) error {
+	v, err := gui.g.View(dynamicViewFeature.Name)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	v.SetOrigin(0, 0)
+	v.SetCursor(0, 0)
+	v.Title = string(ConflictMode)
+	if err := gui.updateDynamicKeybindings(); err != nil {
+		return err
+	}
+	if r.Rebasing() {
+		fmt.Fprintln(v, "Rebasing "+cyan.Sprint(r.State.Branch.Name))
+	} else if r.Merging() {
+		fmt.Fprintln(v, "Merging into "+cyan.Sprint(r.State.Branch.Name))
+	} else {
+		fmt.Fprintln(v, "There is no merge or rebase in progress")
+		return nil
+	}
+	files, err := command.Unmerged(r)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(v, "\nAll conflicts fixed (\"c\" to continue, \"a\" to abort)")
+		return nil
+	}
+	fmt.Fprintln(v, "\nUnmerged paths:")
+	fmt.Fprintln(v, "")
+	for _, f := range files {
+		fmt.Fprintln(v, " "+red.Sprint(string(f.X)+string(f.Y)+" "+f.Name))
+	}
+	fmt.Fprintln(v, "\n(edit the file and \"space\" to mark as resolved)")
+	return gui.statusCursorDown(gui.g, v)
+}
+
+// returns the unmerged file under the cursor, nil if there is none
+func (gui *Gui) selectedConflict(v *gocui.View) (*git.File, error) {
+	_, cy := v.Cursor()
+	line, err := v.Line(cy)
+	if err != nil {
+		return nil, nil
+	}
+	files, err := command.Unmerged(gui.getSelectedRepository())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(strings.TrimSpace(line), " "+f.Name) {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// keep our version of the selected file
+func (gui *Gui) conflictOurs(g *gocui.Gui, v *gocui.View) error {
+	return gui.resolveConflict(g, v, command.Ours)
+}
+
+// keep their version of the selected file
+func (gui *Gui) conflictTheirs(g *gocui.Gui, v *gocui.View) error {
+	return gui.resolveConflict(g, v, command.Theirs)
+}
+
+// replace the selected file with the given side and mark it as resolved
+func (gui *Gui) resolveConflict(g *gocui.Gui, v *gocui.View, side command.ConflictSide) error {
+	r := gui.getSelectedRepository()
+	f, err := gui.selectedConflict(v)
+	if err != nil || f == nil {
+		return err
+	}
+	if err := command.CheckoutConflict(r, f, side); err != nil {
+		return gui.openErrorView(g, err.Error(), "the file should be resolved manually", dynamicViewFeature.Name)
+	}
+	return gui.markConflictResolved(g, v)
+}
+
+// mark the selected file as resolved, it is expected to be edited already
+func (gui *Gui) markConflictResolved(g *gocui.Gui, v *gocui.View) error {
+	r := gui.getSelectedRepository()
+	f, err := gui.selectedConflict(v)
+	if err != nil || f == nil {
+		return err
+	}
+	if err := command.Add(r, f, &command.AddOptions{}); err != nil {
+		return gui.openErrorView(g, err.Error(), "the file couldn't be marked as resolved", dynamicViewFeature.Name)
+	}
+	return gui.initFocusConflicts(r)
+}
+
+// show the conflicting hunks of the selected file
+func (gui *Gui) conflictDiff(g *gocui.Gui, v *gocui.View) error {
+	f, err := gui.selectedConflict(v)
+	if err != nil || f == nil {
+		return err
+	}
+	out, err := command.DiffConflict(f)
+	v.Clear()
+	v.Title = string(ConflictDiffMode)
+	if err := gui.updateDynamicKeybindings(); err != nil {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintln(v, "Can't get diff")
+		return nil
+	}
+	fmt.Fprintln(v, strings.Join(colorizeConflict(out), "\n"))
+	return nil
+}
+
+// return to the unmerged files
+func (gui *Gui) conflictStat(g *gocui.Gui, v *gocui.View) error {
+	return gui.initFocusConflicts(gui.getSelectedRepository())
+}
+
+// conclude the merge or continue the rebase with the resolved files
+func (gui *Gui) continueConflict(g *gocui.Gui, v *gocui.View) error {
+	r := gui.getSelectedRepository()
+	if err := command.Continue(context.Background(), r); err != nil {
+		// the rebase may stop at the conflicts of the next commit
+		if errors.Is(err, gerr.ErrRebaseConflict) && r.Rebasing() {
+			r.State.Message = err.Error()
+			r.SetWorkStatus(git.Conflicted)
+			return gui.initFocusConflicts(r)
+		}
+		return gui.openErrorView(g, err.Error(), "resolve all the conflicts before continuing", dynamicViewFeature.Name)
+	}
+	return gui.conflictConcluded(r)
+}
+
+// abort the merge or rebase from focus mode
+func (gui *Gui) abortConflictFocus(g *gocui.Gui, v *gocui.View) error {
+	r := gui.getSelectedRepository()
+	if err := command.Abort(context.Background(), r); err != nil {
+		return gui.openErrorView(g, err.Error(), "the operation should be aborted manually", dynamicViewFeature.Name)
+	}
+	return gui.conflictConcluded(r)
+}
+
+// the branch is moved after a merge or rebase is concluded or aborted
+func (gui *Gui) conflictConcluded(r *git.Repository) error {
+	r.State.Branch.InitializeCommits(r)
+	if err := gui.renderCommits(r); err != nil {
+		return err
+	}
+	return gui.initFocusStat(r)
+}
+
+// aborts the merge or rebase of the selected repository that is stopped on a
+// conflict
+func (gui *Gui) abortConflict(g *gocui.Gui, v *gocui.View) error {
+	r := gui.getSelectedRepository()
+	if r == nil || r.WorkStatus() != git.Conflicted {
+		return nil
+	}
+	if err := command.Abort(context.Background(), r); err != nil {
+		return gui.openErrorView(g, err.Error(), "the operation should be aborted manually", mainViewFeature.Name)
+	}
+	return nil
+}
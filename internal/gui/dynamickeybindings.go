@@ -202,6 +202,14 @@ func (gui *Gui) updateDynamicKeybindings() error {
 				Display:     "t",
 				Description: "stash",
 				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'x',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.conflictStat,
+				Display:     "x",
+				Description: "conflicts",
+				Vital:       false,
 			}, {
 				View:        dynamicViewFeature.Name,
 				Key:         gocui.KeyArrowDown,
@@ -290,6 +298,128 @@ func (gui *Gui) updateDynamicKeybindings() error {
 			},
 		}
 		keybindings = append(keybindings, caseBindings...)
+	case ConflictMode:
+		caseBindings := []*KeyBinding{
+			{
+				View:        dynamicViewFeature.Name,
+				Key:         'd',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.conflictDiff,
+				Display:     "d",
+				Description: "diff",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'o',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.conflictOurs,
+				Display:     "o",
+				Description: "ours",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         't',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.conflictTheirs,
+				Display:     "t",
+				Description: "theirs",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         gocui.KeySpace,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.markConflictResolved,
+				Display:     "space",
+				Description: "resolved",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'c',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.continueConflict,
+				Display:     "c",
+				Description: "continue",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'a',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.abortConflictFocus,
+				Display:     "a",
+				Description: "abort",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         's',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.statusStat,
+				Display:     "s",
+				Description: "status",
+				Vital:       false,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         gocui.KeyArrowDown,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.statusCursorDown,
+				Display:     "↓",
+				Description: "Down",
+				Vital:       false,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         gocui.KeyArrowUp,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.statusCursorUp,
+				Display:     "↑",
+				Description: "Up",
+				Vital:       false,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'j',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.statusCursorDown,
+				Display:     "j",
+				Description: "Down",
+				Vital:       false,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         'k',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.statusCursorUp,
+				Display:     "k",
+				Description: "Up",
+				Vital:       false,
+			},
+		}
+		keybindings = append(keybindings, caseBindings...)
+	case ConflictDiffMode:
+		caseBindings := []*KeyBinding{
+			{
+				View:        dynamicViewFeature.Name,
+				Key:         's',
+				Modifier:    gocui.ModNone,
+				Handler:     gui.conflictStat,
+				Display:     "s",
+				Description: "conflicts",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         gocui.KeyPgup,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.dpageUp,
+				Display:     "pg up",
+				Description: "Page up",
+				Vital:       true,
+			}, {
+				View:        dynamicViewFeature.Name,
+				Key:         gocui.KeyPgdn,
+				Modifier:    gocui.ModNone,
+				Handler:     gui.dpageDown,
+				Display:     "pg down",
+				Description: "Page Down",
+				Vital:       true,
+			},
+		}
+		keybindings = append(keybindings, caseBindings...)
 	default:
 
 	}
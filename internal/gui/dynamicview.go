@@ -24,6 +24,10 @@ const (
 	StatusMode DynamicViewMode = " Repository Status "
 	// FileDiffMode when dynamic mode morphed into file diff mode
 	FileDiffMode DynamicViewMode = " File Diffs "
+	// ConflictMode when dynamic mode morphed into unmerged files mode
+	ConflictMode DynamicViewMode = " Conflicts "
+	// ConflictDiffMode when dynamic mode morphed into conflict diff mode
+	ConflictDiffMode DynamicViewMode = " Conflict Diffs "
 )
 
 // shows the stats of current commit
@@ -3,6 +3,7 @@ package gui
 import (
 	"fmt"
 
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/jroimartin/gocui"
 )
 
@@ -26,6 +27,27 @@ func (gui *Gui) openErrorView(g *gocui.Gui, message, note, returnViewName string
 	return gui.focusToView(errorViewFeature.Name)
 }
 
+// open the error view with the command, exit code and output of the last
+// failed job of the selected repository
+func (gui *Gui) openErrorDetailView(g *gocui.Gui, v *gocui.View) error {
+	r := gui.getSelectedRepository()
+	if r == nil || r.State.LastError == nil {
+		return nil
+	}
+	maxX, maxY := g.Size()
+	errorReturnView = v.Name()
+	ev, err := g.SetView(errorViewFeature.Name, maxX/2-40, maxY/2-10, maxX/2+40, maxY/2+10)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ev.Title = errorViewFeature.Title + r.Name + " "
+		ev.Wrap = true
+		fmt.Fprint(ev, gerr.Detail(r.State.LastError))
+	}
+	return gui.focusToView(errorViewFeature.Name)
+}
+
 // close the opened error view
 func (gui *Gui) closeErrorView(g *gocui.Gui, v *gocui.View) error {
 
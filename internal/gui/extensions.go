@@ -68,12 +68,30 @@ func (gui *Gui) switchToMergeMode(g *gocui.Gui, v *gocui.View) error {
 	return gui.updateKeyBindingsView(g, mainViewFeature.Name)
 }
 
+// switch the app's mode to rebase
+func (gui *Gui) switchToRebaseMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Mode = rebaseMode
+	return gui.updateKeyBindingsView(g, mainViewFeature.Name)
+}
+
+// switch the app's mode to fast-forward only pull
+func (gui *Gui) switchToFastForwardMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Mode = ffOnlyMode
+	return gui.updateKeyBindingsView(g, mainViewFeature.Name)
+}
+
 // switch the app's mode to checkout
 func (gui *Gui) switchToCheckoutMode(g *gocui.Gui, v *gocui.View) error {
 	gui.State.Mode = checkoutMode
 	return gui.updateKeyBindingsView(g, mainViewFeature.Name)
 }
 
+// switch the app's mode to push
+func (gui *Gui) switchToPushMode(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Mode = pushMode
+	return gui.updateKeyBindingsView(g, mainViewFeature.Name)
+}
+
 // bring the view on the top by its name
 func (gui *Gui) setCurrentViewOnTop(g *gocui.Gui, name string) (*gocui.View, error) {
 	if _, err := g.SetCurrentView(name); err != nil {
@@ -0,0 +1,59 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/jroimartin/gocui"
+)
+
+// open the filter prompt, the main view is filtered while typing
+func (gui *Gui) openFilterView(g *gocui.Gui, v *gocui.View) error {
+	if gui.order == focus {
+		return nil
+	}
+	if _, err := g.SetViewOnTop(filterViewFeature.Name); err != nil {
+		return err
+	}
+	return gui.focusToView(filterViewFeature.Name)
+}
+
+// close the filter prompt and keep the filter applied
+func (gui *Gui) closeFilterView(g *gocui.Gui, v *gocui.View) error {
+	if _, err := g.SetViewOnBottom(filterViewFeature.Name); err != nil {
+		return err
+	}
+	return gui.focusToView(mainViewFeature.Name)
+}
+
+// clear the filter and close the prompt
+func (gui *Gui) clearFilter(g *gocui.Gui, v *gocui.View) error {
+	v.Clear()
+	if err := v.SetCursor(0, 0); err != nil {
+		return err
+	}
+	gui.applyFilter("")
+	return gui.closeFilterView(g, v)
+}
+
+// filterEditor is the editor of the filter prompt. It behaves like the default
+// editor but updates the filter after every key stroke
+func (gui *Gui) filterEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	// the query is a single line
+	if key == gocui.KeyEnter {
+		return
+	}
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+	gui.applyFilter(v.Buffer())
+}
+
+// applies the query to the main view, an empty query removes the filter
+func (gui *Gui) applyFilter(query string) error {
+	query = strings.TrimSpace(query)
+	if len(query) == 0 {
+		gui.State.filter = nil
+	} else {
+		gui.State.filter = git.NewFilter(query)
+	}
+	return gui.refreshVisibleRepositories()
+}
@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"github.com/isacikgoz/gitbatch/internal/git"
 	"github.com/jroimartin/gocui"
 )
 
@@ -109,7 +110,12 @@ func (gui *Gui) focusToRepository(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.initStashedView(r); err != nil {
 		return err
 	}
-	if err := gui.initFocusStat(r); err != nil {
+	// the conflicts are shown first if the repository is waiting for them
+	if r.WorkStatus() == git.Conflicted {
+		if err := gui.initFocusConflicts(r); err != nil {
+			return err
+		}
+	} else if err := gui.initFocusStat(r); err != nil {
 		return err
 	}
 
@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"sort"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/jroimartin/gocui"
+)
+
+// returns the groups that the repository belongs to
+func (gui *Gui) repositoryGroups(r *git.Repository) []string {
+	return gui.State.groups[r.AbsPath]
+}
+
+// returns true if the repository is in the given group
+func (gui *Gui) inGroup(r *git.Repository, group string) bool {
+	for _, g := range gui.repositoryGroups(r) {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the repositories that are shown in the main view, if a group is
+// selected or a filter is applied only the matching repositories are shown
+func (gui *Gui) visibleRepositories() []*git.Repository {
+	if len(gui.State.group) == 0 && gui.State.filter == nil {
+		return gui.State.Repositories
+	}
+	rs := make([]*git.Repository, 0)
+	for _, r := range gui.State.Repositories {
+		if len(gui.State.group) > 0 && !gui.inGroup(r, gui.State.group) {
+			continue
+		}
+		if gui.State.filter != nil && !gui.State.filter.Match(r) {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// returns the sorted names of the groups of the loaded repositories
+func (gui *Gui) groupNames() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, r := range gui.State.Repositories {
+		for _, g := range gui.repositoryGroups(r) {
+			if !seen[g] {
+				seen[g] = true
+				names = append(names, g)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// label of the selected group and filter to be shown on the main view's title
+func (gui *Gui) groupLabel() string {
+	label := ""
+	if len(gui.State.group) > 0 {
+		label = label + "[" + gui.State.group + "]" + ws
+	}
+	if gui.State.filter != nil {
+		label = label + "/" + gui.State.filter.Query + ws
+	}
+	return label
+}
+
+// selects the next group to filter the main view, after the last group all of
+// the repositories are shown again
+func (gui *Gui) nextGroup(g *gocui.Gui, v *gocui.View) error {
+	names := gui.groupNames()
+	next := ""
+	if len(gui.State.group) == 0 && len(names) > 0 {
+		next = names[0]
+	} else {
+		for i, name := range names {
+			if name == gui.State.group && i+1 < len(names) {
+				next = names[i+1]
+				break
+			}
+		}
+	}
+	gui.State.group = next
+	return gui.refreshVisibleRepositories()
+}
+
+// moves the cursor to the top since the visible repositories are changed and
+// re-renders the main view
+func (gui *Gui) refreshVisibleRepositories() error {
+	v, err := gui.g.View(mainViewFeature.Name)
+	if err != nil {
+		return err
+	}
+	if err := v.SetOrigin(0, 0); err != nil {
+		return err
+	}
+	if err := v.SetCursor(0, 0); err != nil {
+		return err
+	}
+	if err := gui.renderTitle(); err != nil {
+		return err
+	}
+	return gui.renderMain()
+}
+
+// adds the repositories of the selected group into the queue. If there is no
+// selected group, the group of the repository under the cursor is used
+func (gui *Gui) markGroup(g *gocui.Gui, v *gocui.View) error {
+	group := gui.State.group
+	if len(group) == 0 {
+		r := gui.getSelectedRepository()
+		if r == nil || len(gui.repositoryGroups(r)) == 0 {
+			return nil
+		}
+		group = gui.repositoryGroups(r)[0]
+	}
+	for _, r := range gui.State.Repositories {
+		if r.WorkStatus().Ready && gui.inGroup(r, group) {
+			if err := gui.addToQueue(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
 	"github.com/isacikgoz/gitbatch/internal/job"
 	"github.com/isacikgoz/gitbatch/internal/load"
+	"github.com/isacikgoz/gitbatch/internal/watch"
 	"github.com/jroimartin/gocui"
 )
 
@@ -19,6 +22,7 @@ type Gui struct {
 	State       guiState
 	mutex       *sync.Mutex
 	order       Layout
+	watcher     *watch.Watcher
 }
 
 // guiState struct holds the repositories, directories, mode and queue of the
@@ -31,6 +35,63 @@ type guiState struct {
 	FailoverQueue *job.Queue
 	targetBranch  string
 	totalBranches []*branchCountMap
+	jobTimeout    time.Duration
+	jobRetry      job.RetryPolicy
+	jobLimits     job.Limits
+	loadLimit     int
+	groups        map[string][]string
+	group         string
+	filter        *git.Filter
+	autoFetch     *job.AutoFetch
+	autoFetched   map[string]bool
+	history       *history.Log
+	historyView   historyState
+	undoJobs      []*job.Job
+	cleanup       cleanupState
+	cleanupBase   string
+	rebase        bool
+	autoStash     bool
+	ffOnly        bool
+}
+
+// Options defines the rules for the initial state of the gui
+type Options struct {
+	// Mode is the initial mode of the gui, defaults to fetch
+	Mode string
+	// Directories are the possible repository paths to be loaded
+	Directories []string
+	// JobTimeout limits the duration of a single job, zero means no limit
+	JobTimeout time.Duration
+	// Retry is the policy of the jobs failing with transient network errors
+	Retry job.RetryPolicy
+	// Concurrency is the maximum number of jobs running at the same time,
+	// zero means the number of logical CPUs
+	Concurrency int
+	// HostConcurrency is the maximum number of jobs running against the same
+	// remote host, zero means no limit
+	HostConcurrency int
+	// LoadConcurrency is the maximum number of repositories loaded at the
+	// same time, zero means the number of logical CPUs
+	LoadConcurrency int
+	// Groups maps the repository directories to the names of the groups they
+	// belong to
+	Groups map[string][]string
+	// AutoFetchInterval is the period of the background fetch, zero disables
+	// it
+	AutoFetchInterval time.Duration
+	// AutoFetch holds the directories of the repositories to be fetched in the
+	// background
+	AutoFetch map[string]bool
+	// History is the log that the jobs are recorded to, nil disables it
+	History *history.Log
+	// Rebase makes the pull rebase the local commits instead of merging
+	Rebase bool
+	// AutoStash stashes the local changes before a rebase
+	AutoStash bool
+	// FFOnly makes the pull and merge modes fast-forward only
+	FFOnly bool
+	// CleanupBase is the branch that the stale branches are merged into
+	CleanupBase string
 }
 
 // this struct encapsulates the name and title of a view. the name of a view is
@@ -67,6 +128,13 @@ const (
 	MergeMode = "merge"
 	// CheckoutMode checkout selected repositories
 	CheckoutMode = "checkout"
+	// PushMode puts the gui in push state
+	PushMode = "push"
+	// RebaseMode rebases the selected repositories onto their upstreams
+	RebaseMode = "rebase"
+	// FastForwardMode pulls the selected repositories only if they can be
+	// fast-forwarded
+	FastForwardMode = "ff-only"
 
 	overview Layout = 0
 	focus    Layout = 1
@@ -79,6 +147,7 @@ var (
 	branchViewFeature        = viewFeature{Name: "branch", Title: " Branches "}
 	batchBranchViewFeature   = viewFeature{Name: "batch-branch", Title: " Select Branch "}
 	suggestBranchViewFeature = viewFeature{Name: "suggest-branch", Title: " Enter New Branch Name "}
+	filterViewFeature        = viewFeature{Name: "filter", Title: " Filter (name, branch, path, dirty, behind>0, ahead>0, no-upstream, failed, conflicted) "}
 	remoteViewFeature        = viewFeature{Name: "remotes", Title: " Remotes "}
 	remoteBranchViewFeature  = viewFeature{Name: "remotebranches", Title: " Remote Branches "}
 	commitViewFeature        = viewFeature{Name: "commits", Title: " Commits "}
@@ -89,31 +158,59 @@ var (
 	errorViewFeature         = viewFeature{Name: "error", Title: " Error "}
 	dynamicViewFeature       = viewFeature{Name: "dynamic", Title: " Dynamic "}
 	stashViewFeature         = viewFeature{Name: "stash", Title: " Stash "}
+	historyViewFeature       = viewFeature{Name: "history", Title: " History "}
+	historyFilterViewFeature = viewFeature{Name: "history-filter", Title: " Filter (job, outcome, repository, branch, date, weekday) "}
+	undoViewFeature          = viewFeature{Name: "undo", Title: " Undo Batch "}
+	cleanupViewFeature       = viewFeature{Name: "cleanup", Title: " Stale Branches "}
 
 	fetchMode    = mode{ModeID: FetchMode, DisplayString: "Fetch", CommandString: "fetch"}
 	pullMode     = mode{ModeID: PullMode, DisplayString: "Pull", CommandString: "pull"}
 	mergeMode    = mode{ModeID: MergeMode, DisplayString: "Merge", CommandString: "merge"}
 	checkoutMode = mode{ModeID: CheckoutMode, DisplayString: "Checkout", CommandString: "checkout"}
+	pushMode     = mode{ModeID: PushMode, DisplayString: "Push", CommandString: "push"}
+	rebaseMode   = mode{ModeID: RebaseMode, DisplayString: "Rebase", CommandString: "rebase"}
+	ffOnlyMode   = mode{ModeID: FastForwardMode, DisplayString: "Fast-forward", CommandString: "pull --ff-only"}
 
-	modes = []mode{fetchMode, pullMode, mergeMode}
+	modes = []mode{fetchMode, pullMode, mergeMode, rebaseMode, ffOnlyMode, pushMode}
 	// mainViews = []viewFeature{mainViewFeature, commitViewFeature, dynamicViewFeature, remoteViewFeature, remoteBranchViewFeature, branchViewFeature, stashViewFeature}
 	loaded = make(chan bool)
 )
 
 // New creates a Gui object and fill it's state related entities
-func New(mode string, directories []string) (*Gui, error) {
+func New(o *Options) (*Gui, error) {
 	initialState := guiState{
-		Directories:   directories,
+		Directories:   o.Directories,
 		Mode:          fetchMode,
 		Queue:         job.CreateJobQueue(),
 		FailoverQueue: job.CreateJobQueue(),
+		jobTimeout:    o.JobTimeout,
+		jobRetry:      o.Retry,
+		jobLimits: job.Limits{
+			Workers: o.Concurrency,
+			PerHost: o.HostConcurrency,
+		},
+		loadLimit:   o.LoadConcurrency,
+		groups:      o.Groups,
+		autoFetched: o.AutoFetch,
+		history:     o.History,
+		rebase:      o.Rebase,
+		autoStash:   o.AutoStash,
+		ffOnly:      o.FFOnly,
+		cleanupBase: o.CleanupBase,
 	}
+	initialState.Queue.SetLimits(initialState.jobLimits)
+	initialState.Queue.SetHistory(initialState.history)
 	gui := &Gui{
 		State: initialState,
 		mutex: &sync.Mutex{},
 	}
+	if o.AutoFetchInterval > 0 {
+		gui.State.autoFetch = job.NewAutoFetch(o.AutoFetchInterval, gui.autoFetchRepositories)
+		gui.State.autoFetch.Timeout = o.JobTimeout
+		gui.State.autoFetch.Limits = gui.State.jobLimits
+	}
 	for _, m := range modes {
-		if string(m.ModeID) == mode {
+		if string(m.ModeID) == o.Mode {
 			gui.State.Mode = m
 			break
 		}
@@ -136,8 +233,17 @@ func (gui *Gui) Run() error {
 	g.InputEsc = true
 	g.SetManagerFunc(gui.layout)
 
+	// refresh the repositories when they are changed outside of the gui
+	gui.watcher = watch.New(500*time.Millisecond, 2*time.Second)
+	defer gui.watcher.Close()
+
+	if gui.State.autoFetch != nil {
+		gui.State.autoFetch.Start()
+		defer gui.State.autoFetch.Stop()
+	}
+
 	// load repositories in background asynchronously
-	go load.AsyncLoad(gui.State.Directories, gui.loadRepository, loaded)
+	go load.AsyncLoad(gui.State.Directories, gui.loadRepository, loaded, gui.State.loadLimit)
 
 	if err := gui.generateKeybindings(); err != nil {
 		return err
@@ -163,6 +269,7 @@ func (gui *Gui) loadRepository(r *git.Repository) {
 	// add listener
 	r.On(git.RepositoryUpdated, gui.repositoryUpdated)
 	r.On(git.BranchUpdated, gui.branchUpdated)
+	gui.watcher.Add(r)
 	// update gui
 	gui.repositoryUpdated(nil)
 	gui.renderTitle()
@@ -174,7 +281,7 @@ func (gui *Gui) loadRepository(r *git.Repository) {
 			if err != nil {
 				return
 			}
-			v.Title = mainViewFrameFeature.Title + fmt.Sprintf("(%d) ", len(gui.State.Repositories))
+			v.Title = mainViewFrameFeature.Title + gui.groupLabel() + fmt.Sprintf("(%d) ", len(gui.State.Repositories))
 		}
 	}()
 }
@@ -185,7 +292,7 @@ func (gui *Gui) renderTitle() error {
 	if err != nil {
 		return err
 	}
-	v.Title = mainViewFrameFeature.Title + fmt.Sprintf("(%d/%d) ", len(gui.State.Repositories), len(gui.State.Directories))
+	v.Title = mainViewFrameFeature.Title + gui.groupLabel() + fmt.Sprintf("(%d/%d) ", len(gui.State.Repositories), len(gui.State.Directories))
 	return nil
 }
 
@@ -0,0 +1,207 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isacikgoz/gitbatch/internal/history"
+	"github.com/jroimartin/gocui"
+)
+
+// historyState holds the batches read from the operation log and the state of
+// the history view
+type historyState struct {
+	batches  []*history.Batch
+	query    string
+	index    int
+	expanded map[string]bool
+}
+
+// open the history of the jobs, the latest batch comes first
+func (gui *Gui) openHistoryView(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.history == nil {
+		return nil
+	}
+	entries, err := gui.State.history.Entries()
+	if err != nil {
+		return gui.openErrorView(g, err.Error(), "the history at "+gui.State.history.Path+" couldn't be read", mainViewFeature.Name)
+	}
+	gui.State.historyView = historyState{
+		batches:  history.Batches(entries),
+		expanded: make(map[string]bool),
+	}
+	maxX, maxY := g.Size()
+	hv, err := g.SetView(historyViewFeature.Name, 2, 1, maxX-3, maxY-6)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		hv.Title = historyViewFeature.Title
+		hv.Wrap = false
+		hv.Autoscroll = false
+	}
+	fv, err := g.SetView(historyFilterViewFeature.Name, 2, maxY-5, maxX-3, maxY-3)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		fv.Title = historyFilterViewFeature.Title
+		fv.Editable = true
+		fv.Editor = gocui.EditorFunc(gui.historyFilterEditor)
+		fv.Wrap = false
+		fv.Autoscroll = false
+	}
+	if err := gui.renderHistory(); err != nil {
+		return err
+	}
+	return gui.focusToView(historyViewFeature.Name)
+}
+
+// close the history and its filter prompt
+func (gui *Gui) closeHistoryView(g *gocui.Gui, v *gocui.View) error {
+	g.DeleteView(historyFilterViewFeature.Name)
+	if err := g.DeleteView(historyViewFeature.Name); err != nil {
+		return nil
+	}
+	return gui.closeViewCleanup(mainViewFeature.Name)
+}
+
+// move the selection to the next batch
+func (gui *Gui) historyCursorDown(g *gocui.Gui, v *gocui.View) error {
+	gui.State.historyView.index++
+	return gui.renderHistory()
+}
+
+// move the selection to the previous batch
+func (gui *Gui) historyCursorUp(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.historyView.index > 0 {
+		gui.State.historyView.index--
+	}
+	return gui.renderHistory()
+}
+
+// show or hide the jobs of the selected batch
+func (gui *Gui) toggleHistoryBatch(g *gocui.Gui, v *gocui.View) error {
+	hs := &gui.State.historyView
+	batches := history.Filter(hs.batches, hs.query)
+	if hs.index < len(batches) {
+		id := batches[hs.index].ID
+		hs.expanded[id] = !hs.expanded[id]
+	}
+	return gui.renderHistory()
+}
+
+// focus to the filter prompt of the history
+func (gui *Gui) openHistoryFilterView(g *gocui.Gui, v *gocui.View) error {
+	return gui.focusToView(historyFilterViewFeature.Name)
+}
+
+// return to the history and keep the filter applied
+func (gui *Gui) closeHistoryFilterView(g *gocui.Gui, v *gocui.View) error {
+	return gui.focusToView(historyViewFeature.Name)
+}
+
+// clear the filter of the history and return to it
+func (gui *Gui) clearHistoryFilter(g *gocui.Gui, v *gocui.View) error {
+	v.Clear()
+	if err := v.SetCursor(0, 0); err != nil {
+		return err
+	}
+	gui.State.historyView.query = ""
+	if err := gui.renderHistory(); err != nil {
+		return err
+	}
+	return gui.closeHistoryFilterView(g, v)
+}
+
+// historyFilterEditor updates the history after every key stroke
+func (gui *Gui) historyFilterEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	if key == gocui.KeyEnter {
+		return
+	}
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+	gui.State.historyView.query = strings.TrimSpace(v.Buffer())
+	gui.State.historyView.index = 0
+	gui.renderHistory()
+}
+
+// renders the batches matching the filter, the jobs of the expanded batches
+// are listed under them
+func (gui *Gui) renderHistory() error {
+	v, err := gui.g.View(historyViewFeature.Name)
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	hs := &gui.State.historyView
+	batches := history.Filter(hs.batches, hs.query)
+	if len(batches) == 0 {
+		fmt.Fprintln(v, tab+"no records")
+		return nil
+	}
+	if hs.index >= len(batches) {
+		hs.index = len(batches) - 1
+	}
+	lines, si := 0, 0
+	for i, b := range batches {
+		if i == hs.index {
+			si = lines
+			fmt.Fprintf(v, "%s%s\n", ws, green.Sprint(renderBatch(b)))
+		} else {
+			fmt.Fprintf(v, "%s%s\n", tab, renderBatch(b))
+		}
+		lines++
+		if !hs.expanded[b.ID] {
+			continue
+		}
+		for _, e := range b.Entries {
+			fmt.Fprintf(v, "%s%s%s\n", tab, tab+ws+ws, renderHistoryEntry(e))
+			lines++
+		}
+	}
+	return adjustAnchor(si, lines, v)
+}
+
+// renderBatch returns the summary line of a batch
+func renderBatch(b *history.Batch) string {
+	t := b.Time.Local()
+	s := t.Format("Mon 2006-01-02 15:04") + sep + strings.Join(b.Jobs(), ",") + sep + fmt.Sprintf("%d repositories", len(b.Entries))
+	if n := b.Failed(); n > 0 {
+		s += ", " + red.Sprintf("%d failed", n)
+	}
+	return s
+}
+
+// renderHistoryEntry returns the outcome of a single job, the moved HEAD is
+// shown as a range of short hashes
+func renderHistoryEntry(e *history.Entry) string {
+	var symbol string
+	switch e.Outcome {
+	case history.Succeeded:
+		symbol = green.Sprint(successSymbol)
+	case history.Cancelled:
+		symbol = yellow.Sprint(cancelSymbol)
+	default:
+		symbol = red.Sprint(failSymbol)
+	}
+	s := symbol + ws + e.Job + ws + cyan.Sprint(e.Repository)
+	if len(e.Branch) > 0 {
+		s += ws + e.Branch
+	}
+	if e.Changed() {
+		s += sep + shortHash(e.Before) + ".." + shortHash(e.After)
+	}
+	s += sep + (time.Duration(e.Duration) * time.Millisecond).String()
+	if len(e.Error) > 0 {
+		s += sep + red.Sprint(e.Error)
+	}
+	return s
+}
+
+func shortHash(h string) string {
+	if len(h) > hashLength {
+		return h[:hashLength]
+	}
+	return h
+}
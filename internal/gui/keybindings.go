@@ -200,6 +200,14 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "p",
 			Description: "Pull mode",
 			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'F',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.switchToFastForwardMode,
+			Display:     "F",
+			Description: "Fast-forward only pull mode",
+			Vital:       false,
 		}, {
 			View:        mainViewFeature.Name,
 			Key:         'm',
@@ -208,6 +216,14 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "m",
 			Description: "Merge mode",
 			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'r',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.switchToRebaseMode,
+			Display:     "r",
+			Description: "Rebase mode",
+			Vital:       false,
 		}, {
 			View:        mainViewFeature.Name,
 			Key:         'c',
@@ -216,6 +232,14 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "c",
 			Description: "Checkout mode",
 			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'P',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.switchToPushMode,
+			Display:     "P",
+			Description: "Push mode",
+			Vital:       false,
 		}, {
 			View:        mainViewFeature.Name,
 			Key:         gocui.KeyTab,
@@ -312,6 +336,14 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "enter",
 			Description: "Start",
 			Vital:       true,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'x',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.cancelQueue,
+			Display:     "x",
+			Description: "Cancel running jobs",
+			Vital:       false,
 		}, {
 			View:        mainViewFeature.Name,
 			Key:         gocui.KeyCtrlSpace,
@@ -328,6 +360,30 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "backspace",
 			Description: "Deselect All",
 			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         '/',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openFilterView,
+			Display:     "/",
+			Description: "Filter",
+			Vital:       true,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'g',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.nextGroup,
+			Display:     "g",
+			Description: "Filter by next group",
+			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'G',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.markGroup,
+			Display:     "G",
+			Description: "Select All in group",
+			Vital:       false,
 		}, {
 			View:        mainViewFeature.Name,
 			Key:         'h',
@@ -368,6 +424,46 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "d",
 			Description: "Sort repositories by Modification date",
 			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'e',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openErrorDetailView,
+			Display:     "e",
+			Description: "Show error detail",
+			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'H',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openHistoryView,
+			Display:     "H",
+			Description: "Show history",
+			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'U',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openUndoView,
+			Display:     "U",
+			Description: "Undo last batch",
+			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'C',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openCleanupView,
+			Display:     "C",
+			Description: "Clean up stale branches",
+			Vital:       false,
+		}, {
+			View:        mainViewFeature.Name,
+			Key:         'A',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.abortConflict,
+			Display:     "A",
+			Description: "Abort merge/rebase",
+			Vital:       false,
 		}, {
 			View:        "",
 			Key:         gocui.KeyCtrlC,
@@ -432,6 +528,22 @@ func (gui *Gui) generateKeybindings() error {
 			Display:     "a",
 			Description: "add new branch",
 			Vital:       true,
+		}, {
+			View:        filterViewFeature.Name,
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.clearFilter,
+			Display:     "esc",
+			Description: "Clear filter",
+			Vital:       true,
+		}, {
+			View:        filterViewFeature.Name,
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeFilterView,
+			Display:     "enter",
+			Description: "Apply filter",
+			Vital:       true,
 		}, {
 			View:        suggestBranchViewFeature.Name,
 			Key:         gocui.KeyEsc,
@@ -633,6 +745,180 @@ func (gui *Gui) generateKeybindings() error {
 			Description: "Cursor Down",
 			Vital:       false,
 		},
+		// History View
+		{
+			View:        historyViewFeature.Name,
+			Key:         'q',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeHistoryView,
+			Display:     "q",
+			Description: "Close/Cancel",
+			Vital:       true,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeHistoryView,
+			Display:     "esc",
+			Description: "Close/Cancel",
+			Vital:       false,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         gocui.KeyArrowDown,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.historyCursorDown,
+			Display:     "↓",
+			Description: "Cursor Down",
+			Vital:       true,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         gocui.KeyArrowUp,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.historyCursorUp,
+			Display:     "↑",
+			Description: "Cursor Up",
+			Vital:       true,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         'j',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.historyCursorDown,
+			Display:     "j",
+			Description: "Cursor Down",
+			Vital:       false,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         'k',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.historyCursorUp,
+			Display:     "k",
+			Description: "Cursor Up",
+			Vital:       false,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.toggleHistoryBatch,
+			Display:     "enter",
+			Description: "Show/Hide jobs",
+			Vital:       true,
+		}, {
+			View:        historyViewFeature.Name,
+			Key:         '/',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.openHistoryFilterView,
+			Display:     "/",
+			Description: "Filter",
+			Vital:       true,
+		}, {
+			View:        historyFilterViewFeature.Name,
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.clearHistoryFilter,
+			Display:     "esc",
+			Description: "Clear filter",
+			Vital:       true,
+		}, {
+			View:        historyFilterViewFeature.Name,
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeHistoryFilterView,
+			Display:     "enter",
+			Description: "Apply filter",
+			Vital:       true,
+		},
+		// Undo View
+		{
+			View:        undoViewFeature.Name,
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.confirmUndo,
+			Display:     "enter",
+			Description: "Undo",
+			Vital:       true,
+		}, {
+			View:        undoViewFeature.Name,
+			Key:         'q',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeUndoView,
+			Display:     "q",
+			Description: "Close/Cancel",
+			Vital:       true,
+		}, {
+			View:        undoViewFeature.Name,
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeUndoView,
+			Display:     "esc",
+			Description: "Close/Cancel",
+			Vital:       false,
+		},
+		// Cleanup View
+		{
+			View:        cleanupViewFeature.Name,
+			Key:         gocui.KeyArrowDown,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.cleanupCursorDown,
+			Display:     "↓",
+			Description: "Cursor Down",
+			Vital:       true,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         gocui.KeyArrowUp,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.cleanupCursorUp,
+			Display:     "↑",
+			Description: "Cursor Up",
+			Vital:       true,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         'j',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.cleanupCursorDown,
+			Display:     "j",
+			Description: "Cursor Down",
+			Vital:       false,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         'k',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.cleanupCursorUp,
+			Display:     "k",
+			Description: "Cursor Up",
+			Vital:       false,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         gocui.KeySpace,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.toggleCleanupBranch,
+			Display:     "space",
+			Description: "Select",
+			Vital:       true,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.confirmCleanup,
+			Display:     "enter",
+			Description: "Delete selected",
+			Vital:       true,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         'q',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeCleanupView,
+			Display:     "q",
+			Description: "Close/Cancel",
+			Vital:       true,
+		}, {
+			View:        cleanupViewFeature.Name,
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.closeCleanupView,
+			Display:     "esc",
+			Description: "Close/Cancel",
+			Vital:       false,
+		},
 		// Error View
 		{
 			View:        errorViewFeature.Name,
@@ -709,12 +995,21 @@ func (gui *Gui) updateKeyBindingsView(g *gocui.Gui, viewName string) error {
 	case PullMode:
 		v.BgColor = gocui.ColorMagenta
 		modeLabel = pullSymbol + ws + "PULL"
+	case FastForwardMode:
+		v.BgColor = gocui.ColorMagenta
+		modeLabel = fastForwardSymbol + ws + "FF-ONLY"
 	case MergeMode:
 		v.BgColor = gocui.ColorCyan
 		modeLabel = mergeSymbol + ws + "MERGE"
+	case RebaseMode:
+		v.BgColor = gocui.ColorRed
+		modeLabel = rebaseSymbol + ws + "REBASE"
 	case CheckoutMode:
 		v.BgColor = gocui.ColorGreen
 		modeLabel = checkoutSymbol + ws + "CHECKOUT"
+	case PushMode:
+		v.BgColor = gocui.ColorYellow
+		modeLabel = pushSymbol + ws + "PUSH"
 	default:
 		modeLabel = "No mode selected"
 	}
@@ -76,6 +76,17 @@ func (gui *Gui) overviewLayout(g *gocui.Gui) error {
 		v.Autoscroll = false
 		g.SetViewOnBottom(v.Name())
 	}
+	if v, err := g.SetView(filterViewFeature.Name, 0, maxY-5, maxX-1, maxY-3); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = filterViewFeature.Title
+		v.Editable = true
+		v.Editor = gocui.EditorFunc(gui.filterEditor)
+		v.Wrap = false
+		v.Autoscroll = false
+		g.SetViewOnBottom(v.Name())
+	}
 	if v, err := g.SetView(stashViewFeature.Name, -1*int(0.20*float32(maxX)), 0, -1, maxY); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
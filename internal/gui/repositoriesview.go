@@ -1,6 +1,8 @@
 package gui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -21,7 +23,7 @@ func (gui *Gui) renderMain() error {
 		return err
 	}
 	mainView.Clear()
-	for _, r := range gui.State.Repositories {
+	for _, r := range gui.visibleRepositories() {
 		fmt.Fprintln(mainView, gui.repositoryLabel(r))
 	}
 	// while refreshing, refresh sideViews for selected entity, something may
@@ -43,7 +45,7 @@ func (gui *Gui) cursorDown(g *gocui.Gui, v *gocui.View) error {
 	if v != nil {
 		cx, cy := v.Cursor()
 		ox, oy := v.Origin()
-		ly := len(gui.State.Repositories) - 1
+		ly := len(gui.visibleRepositories()) - 1
 
 		// if we are at the end we just return
 		if cy+oy == ly {
@@ -93,7 +95,7 @@ func (gui *Gui) cursorEnd(g *gocui.Gui, v *gocui.View) error {
 		ox, _ := v.Origin()
 		cx, _ := v.Cursor()
 		_, vy := v.Size()
-		lr := len(gui.State.Repositories)
+		lr := len(gui.visibleRepositories())
 		if lr <= vy {
 			if err := v.SetCursor(cx, lr-1); err != nil {
 				return err
@@ -116,7 +118,7 @@ func (gui *Gui) pageDown(g *gocui.Gui, v *gocui.View) error {
 		ox, oy := v.Origin()
 		cx, _ := v.Cursor()
 		_, vy := v.Size()
-		lr := len(gui.State.Repositories)
+		lr := len(gui.visibleRepositories())
 		if lr < vy {
 			return nil
 		}
@@ -163,19 +165,25 @@ func (gui *Gui) pageUp(g *gocui.Gui, v *gocui.View) error {
 // require a better implementation or the slice's order must be synchronized
 // with the views lines
 func (gui *Gui) getSelectedRepository() *git.Repository {
-	if len(gui.State.Repositories) == 0 {
+	rs := gui.visibleRepositories()
+	if len(rs) == 0 {
 		return nil
 	}
 	v, _ := gui.g.View(mainViewFeature.Name)
 	_, oy := v.Origin()
 	_, cy := v.Cursor()
-	return gui.State.Repositories[cy+oy]
+	if cy+oy >= len(rs) {
+		return nil
+	}
+	return rs[cy+oy]
 }
 
 // adds given entity to job queue
 func (gui *Gui) addToQueue(r *git.Repository) error {
 	j := &job.Job{
 		Repository: r,
+		Timeout:    gui.State.jobTimeout,
+		Retry:      gui.State.jobRetry,
 	}
 	switch mode := gui.State.Mode.ModeID; mode {
 	case FetchMode:
@@ -185,17 +193,58 @@ func (gui *Gui) addToQueue(r *git.Repository) error {
 			return nil
 		}
 		j.JobType = job.PullJob
+		if gui.State.rebase {
+			j.Options = &command.PullOptions{
+				RemoteName:  r.State.Remote.Name,
+				Rebase:      true,
+				AutoStash:   gui.State.autoStash,
+				CommandMode: command.ModeLegacy,
+			}
+		} else if gui.State.ffOnly {
+			j.Options = &command.PullOptions{
+				RemoteName:  r.State.Remote.Name,
+				FFOnly:      true,
+				CommandMode: command.ModeNative,
+			}
+		}
+	case FastForwardMode:
+		if r.State.Branch.Upstream == nil {
+			return nil
+		}
+		j.JobType = job.PullJob
+		j.Options = &command.PullOptions{
+			RemoteName:  r.State.Remote.Name,
+			FFOnly:      true,
+			CommandMode: command.ModeNative,
+		}
 	case MergeMode:
 		if r.State.Branch.Upstream == nil {
 			return nil
 		}
 		j.JobType = job.MergeJob
+		j.Options = &command.MergeOptions{
+			FFOnly: gui.State.ffOnly,
+		}
+	case RebaseMode:
+		if r.State.Branch.Upstream == nil {
+			return nil
+		}
+		j.JobType = job.RebaseJob
+		j.Options = &command.RebaseOptions{
+			AutoStash: gui.State.autoStash,
+		}
 	case CheckoutMode:
 		j.JobType = job.CheckoutJob
 		j.Options = &command.CheckoutOptions{
 			TargetRef:      gui.State.targetBranch,
 			CreateIfAbsent: true,
 		}
+	case PushMode:
+		// nothing to publish if the branch is even with its upstream
+		if r.State.Branch.Upstream != nil && r.State.Branch.Pushables == 0 {
+			return nil
+		}
+		j.JobType = job.PushJob
 	default:
 		return nil
 	}
@@ -221,10 +270,12 @@ func (gui *Gui) removeFromQueue(r *git.Repository) error {
 // operation
 func (gui *Gui) startQueue(g *gocui.Gui, v *gocui.View) error {
 	go func(gui_go *Gui) {
-		fails := gui_go.State.Queue.StartJobsAsync()
+		fails := gui_go.State.Queue.StartJobsAsync(context.Background())
 		gui_go.State.Queue = job.CreateJobQueue()
+		gui_go.State.Queue.SetLimits(gui_go.State.jobLimits)
+		gui_go.State.Queue.SetHistory(gui_go.State.history)
 		for j, err := range fails {
-			if err == gerr.ErrAuthenticationRequired {
+			if errors.Is(err, gerr.ErrAuthenticationRequired) {
 				j.Repository.SetWorkStatus(git.Paused)
 				gui_go.State.FailoverQueue.AddJob(j)
 			}
@@ -265,14 +316,27 @@ func (gui *Gui) markRepository(g *gocui.Gui, v *gocui.View) error {
 		if err := gui.removeFromQueue(r); err != nil {
 			return err
 		}
+	} else if r.WorkStatus() == git.Working {
+		// the job will mark the repository as cancelled once it stops, it may
+		// be a background fetch or it may be over meanwhile
+		if err := gui.State.Queue.RemoveFromQueue(r); err != nil && gui.State.autoFetch != nil {
+			gui.State.autoFetch.Cancel(r)
+		}
 	}
 	return nil
 }
 
+// cancel the running jobs and the ones that are waiting to be started
+func (gui *Gui) cancelQueue(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Queue.Cancel()
+	return nil
+}
+
 // add all remaining repositories into the queue. the function does take its
-// current state into account before adding it
+// current state into account before adding it. If a group is selected, only
+// its repositories are added
 func (gui *Gui) markAllRepositories(g *gocui.Gui, v *gocui.View) error {
-	for _, r := range gui.State.Repositories {
+	for _, r := range gui.visibleRepositories() {
 		if r.WorkStatus().Ready {
 			if err := gui.addToQueue(r); err != nil {
 				return err
@@ -285,9 +349,10 @@ func (gui *Gui) markAllRepositories(g *gocui.Gui, v *gocui.View) error {
 }
 
 // remove all repositories from the queue. the function does take its
-// current state into account before removing it
+// current state into account before removing it. If a group is selected, only
+// its repositories are removed
 func (gui *Gui) unmarkAllRepositories(g *gocui.Gui, v *gocui.View) error {
-	for _, r := range gui.State.Repositories {
+	for _, r := range gui.visibleRepositories() {
 		if r.WorkStatus() == git.Queued {
 			if err := gui.removeFromQueue(r); err != nil {
 				return err
@@ -313,3 +378,19 @@ func (gui *Gui) sortByMod(g *gocui.Gui, v *gocui.View) error {
 	gui.renderMain()
 	return nil
 }
+
+// returns the loaded repositories that are fetched in the background, the
+// ones with a job in the queue are left to the user
+func (gui *Gui) autoFetchRepositories() []*git.Repository {
+	rs := make([]*git.Repository, 0)
+	for _, r := range gui.State.Repositories {
+		if !gui.State.autoFetched[r.AbsPath] {
+			continue
+		}
+		if in, _ := gui.State.Queue.IsInTheQueue(r); in {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/isacikgoz/gitbatch/internal/command"
@@ -75,13 +76,21 @@ func (gui *Gui) renderBranches(r *git.Repository) error {
 	}
 	bc := r.State.Branch
 	si := 0
+	// the names are aligned so that the counts are in the same column
+	width := 0
+	for _, b := range bs {
+		if len(b.Name) > width {
+			width = len(b.Name)
+		}
+	}
 	for i, b := range bs {
+		name := align(b.Name, width, true, false)
 		if b.Name == bc.Name {
 			si = i
-			fmt.Fprintln(v, ws+green.Sprint(b.Name))
+			fmt.Fprintln(v, ws+green.Sprint(name)+renderBranchTracking(b))
 			continue
 		}
-		fmt.Fprintln(v, tab+b.Name)
+		fmt.Fprintln(v, tab+name+renderBranchTracking(b))
 	}
 	adjustAnchor(si, len(bs), v)
 	return nil
@@ -201,7 +210,7 @@ func (gui *Gui) resetSideCursors() error {
 // basically does fetch --prune
 func (gui *Gui) syncRemoteBranch(g *gocui.Gui, v *gocui.View) error {
 	r := gui.getSelectedRepository()
-	return command.Fetch(r, &command.FetchOptions{
+	return command.Fetch(context.Background(), r, &command.FetchOptions{
 		RemoteName: r.State.Remote.Name,
 		Prune:      true,
 	})
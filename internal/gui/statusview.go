@@ -22,28 +22,32 @@ func (gui *Gui) initFocusStat(r *git.Repository) error {
 		return err
 	}
 	fmt.Fprintln(v, "On branch "+cyan.Sprint(r.State.Branch.Name))
-	ps, err := strconv.Atoi(r.State.Branch.Pushables)
-	pl, er2 := strconv.Atoi(r.State.Branch.Pullables)
+	ps, pl := r.State.Branch.Pushables, r.State.Branch.Pullables
 	// TODO: move to text-render
-	if err != nil || er2 != nil || r.State.Branch.Upstream == nil {
+	if r.State.Branch.Upstream == nil {
 		fmt.Fprintln(v, "Your branch is not tracking a remote branch.")
+	} else if !r.State.Branch.Counted() {
+		fmt.Fprintln(v, "Your branch is tracking "+cyan.Sprint(r.State.Branch.Upstream.Name)+" but the commits couldn't be counted.")
 	} else {
 		if ps == 0 && pl == 0 {
 			fmt.Fprintln(v, "Your branch is up to date with "+cyan.Sprint(r.State.Branch.Upstream.Name))
 		} else {
 			if ps > 0 && pl > 0 {
 				fmt.Fprintln(v, "Your branch and "+cyan.Sprint(r.State.Branch.Upstream.Name)+" have diverged,")
-				fmt.Fprintln(v, "and have "+yellow.Sprint(r.State.Branch.Pushables)+" and "+yellow.Sprint(r.State.Branch.Pullables)+" different commits each, respectively.")
+				fmt.Fprintln(v, "and have "+yellow.Sprint(ps)+" and "+yellow.Sprint(pl)+" different commits each, respectively.")
 				fmt.Fprintln(v, "(\"pull\" to merge the remote branch into yours)")
 			} else if pl > 0 && ps == 0 {
-				fmt.Fprintln(v, "Your branch is behind "+cyan.Sprint(r.State.Branch.Upstream.Name)+" by "+yellow.Sprint(r.State.Branch.Pullables)+" commit(s).")
+				fmt.Fprintln(v, "Your branch is behind "+cyan.Sprint(r.State.Branch.Upstream.Name)+" by "+yellow.Sprint(pl)+" commit(s).")
 				fmt.Fprintln(v, "(\"pull\" to update your local branch)")
 			} else if ps > 0 && pl == 0 {
-				fmt.Fprintln(v, "Your branch is ahead of "+cyan.Sprint(r.State.Branch.Upstream.Name)+" by "+yellow.Sprint(r.State.Branch.Pushables)+" commit(s).")
+				fmt.Fprintln(v, "Your branch is ahead of "+cyan.Sprint(r.State.Branch.Upstream.Name)+" by "+yellow.Sprint(ps)+" commit(s).")
 				fmt.Fprintln(v, "(\"push\" to publish your local commits)")
 			}
 		}
 	}
+	if r.Rebasing() || r.Merging() {
+		fmt.Fprintln(v, "You have unmerged paths. (\"x\" to resolve conflicts)")
+	}
 	files, err := command.Status(r)
 	if err != nil {
 		return err
@@ -65,7 +69,11 @@ func (gui *Gui) initFocusStat(r *git.Repository) error {
 			fmt.Fprintln(v, "\nChanges to be committed:")
 			fmt.Fprintln(v, "")
 			for _, f := range stagedFiles {
-				fmt.Fprintln(v, " "+green.Sprint(string(f.X)+" "+f.Name))
+				name := f.Name
+				if len(f.OrigName) > 0 {
+					name = f.OrigName + " -> " + f.Name
+				}
+				fmt.Fprintln(v, " "+green.Sprint(string(f.X)+" "+name))
 			}
 		}
 		if len(unstagedFiles) > 0 {
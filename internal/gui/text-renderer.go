@@ -36,18 +36,25 @@ const (
 	maxBranchLength     = 15
 	maxRepositoryLength = 20
 	hashLength          = 7
-
-	ws            = " "
-	queuedSymbol  = "•"
-	workingSymbol = "•"
-	successSymbol = "✔"
-	pauseSymbol   = "॥"
-	failSymbol    = "✗"
+	lastFetchLength     = 7
+
+	ws             = " "
+	queuedSymbol   = "•"
+	workingSymbol  = "•"
+	successSymbol  = "✔"
+	pauseSymbol    = "॥"
+	failSymbol     = "✗"
+	cancelSymbol   = "⊘"
+	conflictSymbol = "!"
+	skipSymbol     = "↷"
 
 	fetchSymbol         = "↓"
 	pullSymbol          = "↓↳"
+	fastForwardSymbol   = "↠"
 	mergeSymbol         = "↳"
+	rebaseSymbol        = "↰"
 	checkoutSymbol      = "↱"
+	pushSymbol          = "↑"
 	modeSeperator       = ""
 	keyBindingSeperator = "░"
 
@@ -76,6 +83,9 @@ func (gui *Gui) repositoryLabel(r *git.Repository) string {
 	line = line + renderRevCount(r, renderRules) + sep
 	line = line + renderBranchName(r, renderRules) + sep
 	line = line + gui.renderRepoName(r, renderRules) + sep
+	if gui.State.autoFetch != nil {
+		line = line + renderLastFetch(r) + sep
+	}
 	line = line + gui.renderStatus(r)
 
 	return line
@@ -109,16 +119,55 @@ func renderBranchName(r *git.Repository, rule *RepositoryDecorationRules) string
 func renderRevCount(r *git.Repository, rule *RepositoryDecorationRules) string {
 	var revCount string
 	b := r.State.Branch
-	if b.Pushables != "?" {
-		revCount = pushable + ws + align(b.Pushables, rule.MaxPushables, false, false) +
-			ws + pullable + ws + align(b.Pullables, rule.MaxPullables, false, false)
+	push, pull := revCounts(b)
+	if b.Counted() {
+		revCount = pushable + ws + align(push, rule.MaxPushables, false, false) +
+			ws + pullable + ws + align(pull, rule.MaxPullables, false, false)
 	} else {
-		revCount = pushable + ws + yellow.Sprint(align(b.Pushables, rule.MaxPushables, false, false)) +
-			ws + pullable + ws + yellow.Sprint(align(b.Pullables, rule.MaxPullables, false, false))
+		revCount = pushable + ws + yellow.Sprint(align(push, rule.MaxPushables, false, false)) +
+			ws + pullable + ws + yellow.Sprint(align(pull, rule.MaxPullables, false, false))
+	}
+	return revCount
+}
+
+// render the ahead and behind counts of a local branch, nothing if it is even
+// with its upstream or not tracking any and a question mark if they are unknown
+func renderBranchTracking(b *git.Branch) string {
+	if b.UpstreamGone {
+		return ws + red.Sprint("gone")
+	}
+	var revCount string
+	if b.Upstream == nil {
+		return revCount
+	}
+	if !b.Counted() {
+		return ws + yellow.Sprint("?")
+	}
+	if b.Pushables > 0 {
+		revCount = revCount + ws + pushable + strconv.Itoa(b.Pushables)
+	}
+	if b.Pullables > 0 {
+		revCount = revCount + ws + pullable + strconv.Itoa(b.Pullables)
 	}
 	return revCount
 }
 
+// the ahead and behind counts of the branch, unknown without an upstream
+func revCounts(b *git.Branch) (string, string) {
+	if !b.Counted() {
+		return "?", "?"
+	}
+	return strconv.Itoa(b.Pushables), strconv.Itoa(b.Pullables)
+}
+
+// render the time of the last successful fetch
+func renderLastFetch(r *git.Repository) string {
+	if r.State.LastFetch.IsZero() {
+		return align("-", lastFetchLength, true, true)
+	}
+	return align(r.State.LastFetch.Format("15:04"), lastFetchLength, true, true)
+}
+
 // render working status of the repository
 func (gui *Gui) renderStatus(r *git.Repository) string {
 	var status string
@@ -134,6 +183,12 @@ func (gui *Gui) renderStatus(r *git.Repository) string {
 		status = yellow.Sprint("! authentication required (u)")
 	} else if r.WorkStatus() == git.Fail {
 		status = red.Sprint(failSymbol) + ws + red.Sprint(r.State.Message)
+	} else if r.WorkStatus() == git.Cancelled {
+		status = yellow.Sprint(cancelSymbol) + ws + yellow.Sprint(r.State.Message)
+	} else if r.WorkStatus() == git.Skipped {
+		status = yellow.Sprint(skipSymbol) + ws + yellow.Sprint(r.State.Message)
+	} else if r.WorkStatus() == git.Conflicted {
+		status = yellow.Sprint(conflictSymbol + ws + r.State.Message + " (tab: resolve, A: abort)")
 	}
 	return status
 }
@@ -150,6 +205,9 @@ func (gui *Gui) renderTableHeader(rule *RepositoryDecorationRules) {
 	header = ws + magenta.Sprint(align("revs", revlen, true, true)) + sep
 	header = header + align(magenta.Sprint("branch"), rule.MaxBranch, true, true) + sep
 	header = header + magenta.Sprint(align("name", rule.MaxName+2, true, true)) + sep
+	if gui.State.autoFetch != nil {
+		header = header + magenta.Sprint(align("fetched", lastFetchLength, true, true)) + sep
+	}
 	fmt.Fprintln(v, header)
 }
 
@@ -160,12 +218,23 @@ func printQueued(r *git.Repository, j *job.Job) string {
 	case job.FetchJob:
 		info = blue.Sprint(queuedSymbol) + ws + "(" + blue.Sprint("fetch") + ws + r.State.Remote.Name + ")"
 	case job.PullJob:
-		info = magenta.Sprint(queuedSymbol) + ws + "(" + magenta.Sprint("pull") + ws + r.State.Remote.Name + ")"
+		action := "pull"
+		if opts, ok := j.Options.(*command.PullOptions); ok && opts.FFOnly {
+			action = "pull --ff-only"
+		}
+		info = magenta.Sprint(queuedSymbol) + ws + "(" + magenta.Sprint(action) + ws + r.State.Remote.Name + ")"
 	case job.MergeJob:
 		info = cyan.Sprint(queuedSymbol) + ws + "(" + cyan.Sprint("merge") + ws + r.State.Branch.Upstream.Name + ")"
+	case job.RebaseJob:
+		info = red.Sprint(queuedSymbol) + ws + "(" + red.Sprint("rebase onto") + ws + r.State.Branch.Upstream.Name + ")"
 	case job.CheckoutJob:
 		refName := j.Options.(*command.CheckoutOptions).TargetRef
 		info = green.Sprint(queuedSymbol) + ws + "(" + cyan.Sprint("switch branch to") + ws + refName + ")"
+	case job.PushJob:
+		info = yellow.Sprint(queuedSymbol) + ws + "(" + yellow.Sprint("push") + ws + r.State.Remote.Name + ")"
+	case job.CleanupJob:
+		n := len(j.Options.(*job.CleanupOptions).Branches)
+		info = red.Sprint(queuedSymbol) + ws + "(" + red.Sprint("delete") + ws + fmt.Sprintf("%d branch(es)", n) + ")"
 	default:
 		info = green.Sprint(queuedSymbol)
 	}
@@ -235,6 +304,22 @@ func colorizeDiff(original string) (colorized []string) {
 	return colorized
 }
 
+// conflictMarker matches the lines of a combined diff that are left by git to
+// separate the sides of a conflict
+var conflictMarker = regexp.MustCompile(`^[ +-]{2}(<{7}|={7}|>{7}|\|{7})`)
+
+// colorizeConflict is colorizeDiff for the combined diff of a conflicted file,
+// the conflict markers are highlighted
+func colorizeConflict(original string) []string {
+	colorized := colorizeDiff(original)
+	for i, line := range strings.Split(original, "\n") {
+		if conflictMarker.MatchString(line) {
+			colorized[i] = yellow.Sprint(line)
+		}
+	}
+	return colorized
+}
+
 // the remote link can be too verbose sometimes, so it is good to trim it
 func trimRemoteURL(url string) (urltype string, shorturl string) {
 	// lets trim the unnecessary .git extension of the url
@@ -381,11 +466,12 @@ func (gui *Gui) renderRules() *RepositoryDecorationRules {
 	rules := &RepositoryDecorationRules{}
 
 	for _, r := range gui.State.Repositories {
-		if len(r.State.Branch.Pullables) > rules.MaxPullables {
-			rules.MaxPullables = len(r.State.Branch.Pullables)
+		push, pull := revCounts(r.State.Branch)
+		if len(pull) > rules.MaxPullables {
+			rules.MaxPullables = len(pull)
 		}
-		if len(r.State.Branch.Pushables) > rules.MaxPushables {
-			rules.MaxPushables = len(r.State.Branch.Pushables)
+		if len(push) > rules.MaxPushables {
+			rules.MaxPushables = len(push)
 		}
 		if len(r.State.Branch.Name) > rules.MaxBranch {
 			rules.MaxBranch = len(r.State.Branch.Name)
@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
+	"github.com/isacikgoz/gitbatch/internal/job"
+	"github.com/jroimartin/gocui"
+)
+
+// open a confirmation for undoing the latest batch that changed any repository
+func (gui *Gui) openUndoView(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.history == nil {
+		return nil
+	}
+	entries, err := gui.State.history.Entries()
+	if err != nil {
+		return gui.openErrorView(g, err.Error(), "the history at "+gui.State.history.Path+" couldn't be read", mainViewFeature.Name)
+	}
+	b := history.LastUndoable(history.Batches(entries))
+	if b == nil {
+		return gui.openErrorView(g, "there is nothing to undo", "only the successful jobs that moved HEAD or switched branch can be undone", mainViewFeature.Name)
+	}
+	gui.State.undoJobs = job.Undo(b, gui.State.Repositories)
+	if len(gui.State.undoJobs) == 0 {
+		return gui.openErrorView(g, "the repositories of the batch are not loaded", "run gitbatch on the directories of the batch", mainViewFeature.Name)
+	}
+	maxX, maxY := g.Size()
+	height := len(gui.State.undoJobs) + 3
+	if height > maxY/2 {
+		height = maxY / 2
+	}
+	uv, err := g.SetView(undoViewFeature.Name, maxX/2-40, maxY/2-height/2-1, maxX/2+40, maxY/2+height/2+1)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		uv.Title = undoViewFeature.Title
+		uv.Wrap = false
+		fmt.Fprintln(uv, renderBatch(b))
+		fmt.Fprintf(uv, "%d repositories will be reset if they are clean and not moved since\n\n", len(gui.State.undoJobs))
+		for _, j := range gui.State.undoJobs {
+			e := j.Options.(*history.Entry)
+			target := shortHash(e.Before)
+			if len(e.BranchAfter) > 0 && e.BranchAfter != e.Branch {
+				target = e.Branch
+			}
+			fmt.Fprintf(uv, "%s%s%s%s\n", tab, cyan.Sprint(j.Repository.Name), sep, target)
+		}
+	}
+	return gui.focusToView(undoViewFeature.Name)
+}
+
+// queue the undo jobs and start them
+func (gui *Gui) confirmUndo(g *gocui.Gui, v *gocui.View) error {
+	for _, j := range gui.State.undoJobs {
+		j.Timeout = gui.State.jobTimeout
+		if err := gui.State.Queue.AddJob(j); err == nil {
+			j.Repository.SetWorkStatus(git.Queued)
+		}
+	}
+	gui.State.undoJobs = nil
+	if err := gui.closeUndoView(g, v); err != nil {
+		return err
+	}
+	return gui.startQueue(g, v)
+}
+
+// close the undo confirmation without undoing
+func (gui *Gui) closeUndoView(g *gocui.Gui, v *gocui.View) error {
+	gui.State.undoJobs = nil
+	if err := g.DeleteView(undoViewFeature.Name); err != nil {
+		return nil
+	}
+	return gui.closeViewCleanup(mainViewFeature.Name)
+}
@@ -0,0 +1,242 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// outcomes of a job
+const (
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Cancelled = "cancelled"
+)
+
+// Entry is the record of a single job. The jobs started together share the
+// same batch id.
+type Entry struct {
+	Batch      string    `json:"batch"`
+	Time       time.Time `json:"time"`
+	Job        string    `json:"job"`
+	Repository string    `json:"repository"`
+	Path       string    `json:"path"`
+	// Branch is the checked out branch before the job, BranchAfter is set
+	// only if the job switched to another branch
+	Branch      string `json:"branch,omitempty"`
+	BranchAfter string `json:"branch_after,omitempty"`
+	// Before and After are the HEAD commits before and after the job
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Duration int64  `json:"duration_ms"`
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+	// Output is the output of the failed git command
+	Output string `json:"output,omitempty"`
+	// Undoes is the id of the batch that is reverted by this job
+	Undoes string `json:"undoes,omitempty"`
+}
+
+// Changed reports whether the job moved the HEAD of the repository or
+// switched its branch
+func (e *Entry) Changed() bool {
+	if len(e.BranchAfter) > 0 && e.BranchAfter != e.Branch {
+		return true
+	}
+	return len(e.Before) > 0 && len(e.After) > 0 && e.Before != e.After
+}
+
+// Undoable reports whether the repository can be reset to the state before
+// the job
+func (e *Entry) Undoable() bool {
+	return e.Outcome == Succeeded && len(e.Undoes) == 0 && len(e.Before) > 0 && e.Changed()
+}
+
+// Batch is the set of jobs that are started together
+type Batch struct {
+	ID      string
+	Time    time.Time
+	Entries []*Entry
+}
+
+// Failed returns the number of jobs that didn't succeed
+func (b *Batch) Failed() int {
+	n := 0
+	for _, e := range b.Entries {
+		if e.Outcome != Succeeded {
+			n++
+		}
+	}
+	return n
+}
+
+// Jobs returns the distinct job types of the batch in the order they are seen
+func (b *Batch) Jobs() []string {
+	seen := make(map[string]bool)
+	jobs := make([]string, 0)
+	for _, e := range b.Entries {
+		if !seen[e.Job] {
+			seen[e.Job] = true
+			jobs = append(jobs, e.Job)
+		}
+	}
+	return jobs
+}
+
+// Undoable returns the entries of the batch that can be undone
+func (b *Batch) Undoable() []*Entry {
+	entries := make([]*Entry, 0)
+	for _, e := range b.Entries {
+		if e.Undoable() {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// LastUndoable returns the latest batch that changed any repository and is not
+// undone yet, nil if there is none. The batches are expected in the order of
+// Batches.
+func LastUndoable(batches []*Batch) *Batch {
+	undone := make(map[string]bool)
+	for _, b := range batches {
+		for _, e := range b.Entries {
+			if len(e.Undoes) > 0 {
+				undone[e.Undoes] = true
+			}
+		}
+	}
+	for _, b := range batches {
+		if !undone[b.ID] && len(b.Undoable()) > 0 {
+			return b
+		}
+	}
+	return nil
+}
+
+// Log is an append-only file of the job records, one json object per line
+type Log struct {
+	Path  string
+	mutex *sync.Mutex
+}
+
+// New returns the log at the given path, the file is created on the first
+// append
+func New(path string) *Log {
+	return &Log{
+		Path:  path,
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Append writes the entries to the end of the log
+func (l *Log) Append(entries ...*Entry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Entries reads the log in the order it is written. The lines that can't be
+// parsed are skipped, e.g. a partial line of an interrupted write.
+func (l *Log) Entries() ([]*Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	f, err := os.Open(l.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := make([]*Entry, 0)
+	s := bufio.NewScanner(f)
+	// the output of a failed command may be long
+	s.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for s.Scan() {
+		e := &Entry{}
+		if err := json.Unmarshal(s.Bytes(), e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, s.Err()
+}
+
+// Batches groups the entries by their batches, the latest batch comes first
+func Batches(entries []*Entry) []*Batch {
+	batches := make([]*Batch, 0)
+	index := make(map[string]*Batch)
+	for _, e := range entries {
+		b, ok := index[e.Batch]
+		if !ok {
+			b = &Batch{ID: e.Batch, Time: e.Time}
+			index[e.Batch] = b
+			batches = append(batches, b)
+		}
+		if e.Time.Before(b.Time) {
+			b.Time = e.Time
+		}
+		b.Entries = append(b.Entries, e)
+	}
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].Time.After(batches[j].Time)
+	})
+	return batches
+}
+
+// Filter returns the batches having at least one entry that matches the
+// query, only the matching entries are kept. The query consists of space
+// separated terms and an entry has to contain all of them in its job type,
+// outcome, repository name, path, branch, error, date (2006-01-02) or weekday.
+func Filter(batches []*Batch, query string) []*Batch {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return batches
+	}
+	filtered := make([]*Batch, 0)
+	for _, b := range batches {
+		fb := &Batch{ID: b.ID, Time: b.Time}
+		for _, e := range b.Entries {
+			if matchAll(e, terms) {
+				fb.Entries = append(fb.Entries, e)
+			}
+		}
+		if len(fb.Entries) > 0 {
+			filtered = append(filtered, fb)
+		}
+	}
+	return filtered
+}
+
+func matchAll(e *Entry, terms []string) bool {
+	t := e.Time.Local()
+	fields := strings.ToLower(strings.Join([]string{
+		e.Job, e.Outcome, e.Repository, e.Path, e.Branch, e.Error,
+		t.Format("2006-01-02"), t.Weekday().String(),
+	}, "\n"))
+	for _, term := range terms {
+		if !strings.Contains(fields, term) {
+			return false
+		}
+	}
+	return true
+}
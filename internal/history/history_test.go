@@ -0,0 +1,107 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	l := New(filepath.Join(dir, "gitbatch", "history.jsonl"))
+	if entries, err := l.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("Test Failed. missing log is not empty")
+	}
+	monday := time.Date(2020, 6, 1, 9, 0, 0, 0, time.Local)
+	if err := l.Append(
+		&Entry{Batch: "1", Time: monday, Job: "pull", Repository: "a", Before: "1", After: "2", Outcome: Succeeded},
+		&Entry{Batch: "1", Time: monday, Job: "pull", Repository: "b", Outcome: Failed, Error: "authentication required"},
+	); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	// a partial line is left by an interrupted write
+	f, _ := os.OpenFile(l.Path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("{\"batch\":\"2\",\n")
+	f.Close()
+	if err := l.Append(&Entry{Batch: "3", Time: monday.Add(24 * time.Hour), Job: "fetch", Repository: "a", Outcome: Succeeded}); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	entries, err := l.Entries()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Test Failed. entries: %d, expected: %d", len(entries), 3)
+	}
+	batches := Batches(entries)
+	if len(batches) != 2 || batches[0].ID != "3" || len(batches[1].Entries) != 2 {
+		t.Errorf("Test Failed. entries are not grouped by batches")
+	}
+	if batches[1].Failed() != 1 || !batches[1].Entries[0].Changed() {
+		t.Errorf("Test Failed. outcomes are not preserved")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	monday := time.Date(2020, 6, 1, 9, 0, 0, 0, time.Local)
+	batches := Batches([]*Entry{
+		{Batch: "1", Time: monday, Job: "pull", Repository: "gitbatch", Outcome: Succeeded},
+		{Batch: "1", Time: monday, Job: "pull", Repository: "dirty-repo", Outcome: Failed},
+		{Batch: "2", Time: monday.Add(24 * time.Hour), Job: "fetch", Repository: "gitbatch", Outcome: Succeeded},
+	})
+	var tests = []struct {
+		input    string
+		batches  int
+		expected int
+	}{
+		{"", 2, 3},
+		{"pull", 1, 2},
+		{"monday pull failed", 1, 1},
+		{"gitbatch", 2, 2},
+		{"2020-06-02", 1, 1},
+		{"push", 0, 0},
+	}
+	for _, test := range tests {
+		filtered := Filter(batches, test.input)
+		n := 0
+		for _, b := range filtered {
+			n += len(b.Entries)
+		}
+		if len(filtered) != test.batches || n != test.expected {
+			t.Errorf("Test Failed. %s inputted, output: %d/%d, expected: %d/%d", test.input, len(filtered), n, test.batches, test.expected)
+		}
+	}
+}
+
+func TestLastUndoable(t *testing.T) {
+	monday := time.Date(2020, 6, 1, 9, 0, 0, 0, time.Local)
+	pull := &Entry{Batch: "1", Time: monday, Job: "pull", Path: "a", Before: "1", After: "2", Outcome: Succeeded}
+	fetch := &Entry{Batch: "2", Time: monday.Add(time.Hour), Job: "fetch", Path: "a", Before: "2", After: "2", Outcome: Succeeded}
+	failed := &Entry{Batch: "3", Time: monday.Add(2 * time.Hour), Job: "merge", Path: "a", Before: "2", After: "3", Outcome: Failed}
+	checkout := &Entry{Batch: "4", Time: monday.Add(3 * time.Hour), Job: "checkout", Path: "a", Branch: "master", BranchAfter: "dev", Before: "2", After: "2", Outcome: Succeeded}
+	undo := &Entry{Batch: "5", Time: monday.Add(4 * time.Hour), Job: "undo", Path: "a", Branch: "dev", BranchAfter: "master", Before: "2", After: "2", Outcome: Succeeded, Undoes: "4"}
+	var tests = []struct {
+		input    []*Entry
+		expected string
+	}{
+		{[]*Entry{fetch}, ""},
+		{[]*Entry{pull, fetch, failed}, "1"},
+		{[]*Entry{pull, checkout}, "4"},
+		{[]*Entry{pull, checkout, undo}, "1"},
+	}
+	for _, test := range tests {
+		output := ""
+		if b := LastUndoable(Batches(test.input)); b != nil {
+			output = b.ID
+		}
+		if output != test.expected {
+			t.Errorf("Test Failed. output: %q, expected: %q", output, test.expected)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// AutoFetch fetches the repositories periodically in the background. If a
+// round takes longer than the interval, the next round is skipped.
+type AutoFetch struct {
+	// Interval is the duration between two rounds
+	Interval time.Duration
+	// Timeout limits the duration of a single fetch, zero means no limit
+	Timeout time.Duration
+	// Limits are the concurrency limits of a round
+	Limits Limits
+
+	repositories func() []*git.Repository
+	cancel       context.CancelFunc
+	// done is closed when the scheduler goroutine returns
+	done chan struct{}
+	// queue is the queue of the running round
+	queue *Queue
+	mutex *sync.Mutex
+}
+
+// NewAutoFetch creates an auto fetch scheduler, repositories is called on
+// every round to get the repositories to be fetched
+func NewAutoFetch(interval time.Duration, repositories func() []*git.Repository) *AutoFetch {
+	return &AutoFetch{
+		Interval:     interval,
+		repositories: repositories,
+		mutex:        &sync.Mutex{},
+	}
+}
+
+// Start starts fetching in the background until Stop is called
+func (a *AutoFetch) Start() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(a.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// the ticker drops the ticks while the round is running
+				a.Fetch(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(a.done)
+}
+
+// Stop stops the scheduler, the running fetches are cancelled and it returns
+// after the running round is over
+func (a *AutoFetch) Stop() {
+	a.mutex.Lock()
+	cancel, done := a.cancel, a.done
+	a.cancel, a.done = nil, nil
+	a.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Cancel cancels the background fetch of the repository if it is in the
+// running round
+func (a *AutoFetch) Cancel(r *git.Repository) error {
+	a.mutex.Lock()
+	q := a.queue
+	a.mutex.Unlock()
+	if q == nil {
+		return fmt.Errorf("there is no job with given repoID")
+	}
+	return q.RemoveFromQueue(r)
+}
+
+// Fetch runs a single round and returns the failed jobs. The repositories that
+// are queued, working or waiting for credentials are skipped, so are the ones
+// whose failure is still to be seen by the user.
+func (a *AutoFetch) Fetch(ctx context.Context) map[*Job]error {
+	q := CreateJobQueue()
+	q.SetLimits(a.Limits)
+	for _, r := range a.repositories() {
+		switch r.WorkStatus() {
+		case git.Queued, git.Working, git.Paused, git.Fail, git.Conflicted:
+			continue
+		}
+		q.AddJob(&Job{
+			JobType:    FetchJob,
+			Repository: r,
+			Timeout:    a.Timeout,
+		})
+	}
+	a.mutex.Lock()
+	a.queue = q
+	a.mutex.Unlock()
+	defer func() {
+		a.mutex.Lock()
+		a.queue = nil
+		a.mutex.Unlock()
+	}()
+	return q.StartJobsAsync(ctx)
+}
@@ -0,0 +1,70 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestAutoFetchFetch(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	a := NewAutoFetch(time.Hour, func() []*git.Repository {
+		return []*git.Repository{r}
+	})
+	var tests = []struct {
+		status  git.WorkStatus
+		fetched bool
+	}{
+		{git.Working, false},
+		{git.Queued, false},
+		{git.Fail, false},
+		{git.Conflicted, false},
+		{git.Available, true},
+	}
+	for _, test := range tests {
+		r.State.LastFetch = time.Time{}
+		r.SetWorkStatus(test.status)
+		if fails := a.Fetch(context.Background()); len(fails) > 0 {
+			t.Errorf("Test Failed. %d jobs failed", len(fails))
+		}
+		if fetched := !r.State.LastFetch.IsZero(); fetched != test.fetched {
+			t.Errorf("Test Failed. status: %v, fetched: %t, expected: %t", test.status, fetched, test.fetched)
+		}
+	}
+}
+
+func TestAutoFetchStart(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	// every round starts after the previous one is over
+	rounds := make(chan struct{})
+	a := NewAutoFetch(10*time.Millisecond, func() []*git.Repository {
+		select {
+		case rounds <- struct{}{}:
+		default:
+		}
+		return []*git.Repository{r}
+	})
+	a.Start()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-rounds:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Test Failed. round is not started")
+		}
+	}
+	a.Stop()
+	if r.State.LastFetch.IsZero() {
+		t.Errorf("Test Failed. repository is not fetched")
+	}
+}
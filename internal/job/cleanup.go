@@ -0,0 +1,61 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// CleanupOptions are the stale branches of a repository selected to be deleted
+type CleanupOptions struct {
+	// Base is the branch that the branches have to be merged into, the default
+	// branch of the remote if it is empty
+	Base string
+	// Branches are the names of the local branches
+	Branches []string
+}
+
+// cleanup deletes the selected branches. The branches are checked again since
+// the repository may be changed after they are reviewed, the current branch and
+// the ones having commits that are not merged into the base are skipped.
+func (j *Job) cleanup(ctx context.Context, opts *CleanupOptions) error {
+	r := j.Repository
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := r.Refresh(); err != nil {
+		return err
+	}
+	stale, err := r.StaleBranches(opts.Base)
+	if err != nil {
+		return err
+	}
+	merged := make(map[string]bool)
+	for _, s := range stale {
+		merged[s.Branch.Name] = s.Merged
+	}
+	branches := make([]string, 0)
+	for _, b := range opts.Branches {
+		if b != r.State.Branch.Name && merged[b] {
+			branches = append(branches, b)
+		}
+	}
+	if len(branches) == 0 {
+		return gerr.ErrBranchNotMerged
+	}
+	if err := command.DeleteBranches(ctx, r, &command.DeleteBranchOptions{
+		Branches: branches,
+		Force:    true,
+	}); err != nil {
+		return err
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = fmt.Sprintf("%d branch(es) deleted", len(branches))
+	if skipped := len(opts.Branches) - len(branches); skipped > 0 {
+		r.State.Message += fmt.Sprintf(", %d skipped", skipped)
+	}
+	return r.Refresh()
+}
@@ -0,0 +1,65 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestCleanup(t *testing.T) {
+	var tests = []struct {
+		branches []string
+		deleted  []string
+		message  string
+		status   git.WorkStatus
+		expected error
+	}{
+		{[]string{"merged", "wip", "master"}, []string{"merged"}, "1 branch(es) deleted, 2 skipped", git.Success, nil},
+		{[]string{"wip"}, nil, gerr.ErrBranchNotMerged.Error(), git.Skipped, gerr.ErrBranchNotMerged},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testutil.LocalRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		for _, args := range [][]string{
+			{"branch", "merged"},
+			{"checkout", "-q", "-b", "wip"},
+			{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", "work in progress"},
+			{"checkout", "-q", "master"},
+		} {
+			if out, err := command.Run(r.AbsPath, "git", args); err != nil {
+				t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+			}
+		}
+		j := &Job{
+			JobType:    CleanupJob,
+			Repository: r,
+			Options:    &CleanupOptions{Branches: test.branches},
+		}
+		if err := j.start(context.Background()); !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. error: %v, expected: %v", err, test.expected)
+		}
+		if r.State.Message != test.message || r.WorkStatus() != test.status {
+			t.Errorf("Test Failed. message: %s, expected: %s", r.State.Message, test.message)
+		}
+		left := make(map[string]bool)
+		for _, b := range r.Branches {
+			left[b.Name] = true
+		}
+		for _, b := range test.deleted {
+			if left[b] {
+				t.Errorf("Test Failed. branch %s is not deleted", b)
+			}
+		}
+		if !left["wip"] || !left["master"] {
+			t.Errorf("Test Failed. unmerged or current branch is deleted")
+		}
+		cleanup()
+	}
+}
@@ -0,0 +1,141 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// the credentials entered by the user are remembered per remote host, so that
+// they are asked only once for all of the repositories on the same host
+var (
+	hostCredentials = make(map[string]*git.Credentials)
+	credentialMutex = &sync.Mutex{}
+)
+
+// SetCredentials remembers the credentials for the remote host of the
+// repository
+func SetCredentials(r *git.Repository, c *git.Credentials) {
+	key, ok := credentialKey(r)
+	if !ok {
+		return
+	}
+	credentialMutex.Lock()
+	defer credentialMutex.Unlock()
+	hostCredentials[key] = c
+}
+
+// SameHost reports whether the repositories share the same remote host and
+// protocol, so that the same credentials can be used for them
+func SameHost(r1, r2 *git.Repository) bool {
+	k1, ok1 := credentialKey(r1)
+	k2, ok2 := credentialKey(r2)
+	return ok1 && ok2 && k1 == k2
+}
+
+// credentials returns the remembered credentials of the repository's host
+func credentials(r *git.Repository) *git.Credentials {
+	key, ok := credentialKey(r)
+	if !ok {
+		return nil
+	}
+	credentialMutex.Lock()
+	defer credentialMutex.Unlock()
+	return hostCredentials[key]
+}
+
+// forgetCredentials removes the credentials of the host if they are still the
+// given ones
+func forgetCredentials(r *git.Repository, c *git.Credentials) {
+	key, ok := credentialKey(r)
+	if !ok {
+		return
+	}
+	credentialMutex.Lock()
+	defer credentialMutex.Unlock()
+	if hostCredentials[key] == c {
+		delete(hostCredentials, key)
+	}
+}
+
+// credentialKey is the protocol and the host of the repository's remote
+func credentialKey(r *git.Repository) (string, bool) {
+	if r.State.Remote == nil || len(r.State.Remote.URL) == 0 {
+		return "", false
+	}
+	protocol, err := git.AuthProtocol(r.State.Remote)
+	if err != nil {
+		return "", false
+	}
+	host, err := git.RemoteHost(r.State.Remote)
+	if err != nil || len(host) == 0 {
+		return "", false
+	}
+	return protocol + "://" + host, true
+}
+
+// authenticate runs the operation with the given credentials. If they are nil
+// and the remote requires authentication, the operation is retried with the
+// credentials remembered for the host and then with the ones of the git
+// credential helpers. The helpers are told whether the credentials worked, the
+// remembered ones may be stored in them too.
+func (j *Job) authenticate(ctx context.Context, given *git.Credentials, run func(c *git.Credentials) error) error {
+	r := j.Repository
+	err := run(given)
+	if given != nil {
+		if err == nil {
+			SetCredentials(r, given)
+			j.approve(ctx, given)
+		}
+		return err
+	}
+	if !errors.Is(err, gerr.ErrAuthenticationRequired) {
+		return err
+	}
+	if c := credentials(r); c != nil {
+		if err = run(c); !errors.Is(err, gerr.ErrAuthenticationRequired) {
+			return err
+		}
+		forgetCredentials(r, c)
+		j.reject(ctx, c)
+	}
+	if !j.usesCredentialHelper() {
+		return err
+	}
+	c, ferr := command.CredentialFill(ctx, r.State.Remote.URL[0])
+	if ferr != nil {
+		return err
+	}
+	if err = run(c); err == nil {
+		SetCredentials(r, c)
+		j.approve(ctx, c)
+	} else if errors.Is(err, gerr.ErrAuthenticationRequired) {
+		j.reject(ctx, c)
+	}
+	return err
+}
+
+// approve stores the working credentials in the git credential helpers
+func (j *Job) approve(ctx context.Context, c *git.Credentials) {
+	if j.usesCredentialHelper() {
+		command.CredentialApprove(ctx, j.Repository.State.Remote.URL[0], c)
+	}
+}
+
+// reject removes the credentials that didn't work from the git credential
+// helpers
+func (j *Job) reject(ctx context.Context, c *git.Credentials) {
+	if j.usesCredentialHelper() {
+		command.CredentialReject(ctx, j.Repository.State.Remote.URL[0], c)
+	}
+}
+
+// git credential helpers only know the user and password of http remotes
+func (j *Job) usesCredentialHelper() bool {
+	protocol, err := git.AuthProtocol(j.Repository.State.Remote)
+	return err == nil && (protocol == git.AuthProtocolHTTP || protocol == git.AuthProtocolHTTPS)
+}
@@ -0,0 +1,103 @@
+package job
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+func TestAuthenticate(t *testing.T) {
+	// isolate from the credential helpers of the user
+	home, err := ioutil.TempDir("", "credential-home")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+
+	remote := func(url string) *git.Repository {
+		return &git.Repository{State: &git.RepositoryState{Remote: &git.Remote{URL: []string{url}}}}
+	}
+	var (
+		r1    = remote("https://example.com/isacikgoz/gitbatch.git")
+		r2    = remote("https://example.com/isacikgoz/dirty-repo.git")
+		r3    = remote("https://example.org/isacikgoz/gitbatch.git")
+		valid = &git.Credentials{User: "user", Password: "pass"}
+	)
+	run := func(c *git.Credentials) error {
+		if c == nil || *c != *valid {
+			return gerr.ErrAuthenticationRequired
+		}
+		return nil
+	}
+	if !SameHost(r1, r2) || SameHost(r1, r3) {
+		t.Errorf("Test Failed. hosts are not compared correctly")
+	}
+	SetCredentials(r1, valid)
+	var tests = []struct {
+		input    *git.Repository
+		given    *git.Credentials
+		expected error
+	}{
+		{r2, nil, nil},
+		{r3, nil, gerr.ErrAuthenticationRequired},
+		{r3, &git.Credentials{User: "user", Password: "wrong"}, gerr.ErrAuthenticationRequired},
+		{r3, valid, nil},
+		{r3, nil, nil},
+	}
+	for _, test := range tests {
+		j := &Job{JobType: FetchJob, Repository: test.input}
+		if err := j.authenticate(context.Background(), test.given, run); err != test.expected {
+			t.Errorf("Test Failed. %s inputted, error: %v, expected: %v", test.input.State.Remote.URL[0], err, test.expected)
+		}
+	}
+}
+
+func TestAuthenticateReject(t *testing.T) {
+	// the credentials are stored by the store helper of an isolated home
+	home, err := ioutil.TempDir("", "credential-home")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+	if err := ioutil.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[credential]\n\thelper = store\n"), 0644); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	r := &git.Repository{State: &git.RepositoryState{Remote: &git.Remote{URL: []string{"https://example.net/isacikgoz/gitbatch.git"}}}}
+	valid := &git.Credentials{User: "user", Password: "pass"}
+	j := &Job{JobType: FetchJob, Repository: r}
+	if err := j.authenticate(context.Background(), valid, func(c *git.Credentials) error { return nil }); err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	stored := func() bool {
+		b, _ := ioutil.ReadFile(filepath.Join(home, ".git-credentials"))
+		return strings.Contains(string(b), "user:pass@example.net")
+	}
+	if !stored() {
+		t.Fatalf("Test Failed. credentials are not stored")
+	}
+	// neither no credentials nor the remembered ones work, the remembered ones
+	// have to be removed from the helper before it is asked for the credentials
+	attempts := 0
+	err = j.authenticate(context.Background(), nil, func(c *git.Credentials) error {
+		if attempts++; attempts <= 2 {
+			return gerr.ErrAuthenticationRequired
+		}
+		return nil
+	})
+	if err != gerr.ErrAuthenticationRequired {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, gerr.ErrAuthenticationRequired)
+	}
+	if stored() {
+		t.Errorf("Test Failed. credentials are not rejected")
+	}
+}
@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
+)
+
+// SetHistory sets the log that the outcome of every job is recorded to, nil
+// disables recording
+func (jq *Queue) SetHistory(l *history.Log) {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
+	jq.history = l
+}
+
+// state is the checked out branch and HEAD of a repository
+type state struct {
+	branch string
+	head   string
+}
+
+// snapshot returns the current state of the repository
+func snapshot(r *git.Repository) state {
+	s := state{head: head(r)}
+	if r.State.Branch != nil {
+		s.branch = r.State.Branch.Name
+	}
+	return s
+}
+
+// record appends the outcome of the job to the history. Failing to write the
+// history doesn't fail the job.
+func (jq *Queue) record(j *Job, before state, started time.Time, err error) {
+	jq.mutex.Lock()
+	l, batch := jq.history, jq.batch
+	jq.mutex.Unlock()
+	if l == nil {
+		return
+	}
+	if len(batch) == 0 {
+		// the job is started on its own
+		batch = newBatchID()
+	}
+	r := j.Repository
+	e := &history.Entry{
+		Batch:      batch,
+		Time:       started,
+		Job:        string(j.JobType),
+		Repository: r.Name,
+		Path:       r.AbsPath,
+		Branch:     before.branch,
+		Before:     before.head,
+		After:      head(r),
+		Duration:   time.Since(started).Milliseconds(),
+		Outcome:    history.Succeeded,
+	}
+	if after := snapshot(r); after.branch != before.branch {
+		e.BranchAfter = after.branch
+	}
+	if u, ok := j.Options.(*history.Entry); ok && j.JobType == UndoJob {
+		e.Undoes = u.Batch
+	}
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		e.Outcome, e.Error = history.Cancelled, r.State.Message
+	case err != nil:
+		e.Outcome, e.Error = history.Failed, r.State.Message
+		var ge *gerr.Error
+		if errors.As(err, &ge) {
+			e.Output = ge.Output
+		}
+	case r.WorkStatus() == git.Fail:
+		e.Outcome, e.Error = history.Failed, r.State.Message
+	}
+	l.Append(e)
+}
+
+// newBatchID returns a unique id for the jobs started together
+func newBatchID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// head returns the hash of the HEAD commit, empty if it can't be resolved
+func head(r *git.Repository) string {
+	ref, err := r.Repo.Head()
+	if err != nil {
+		return ""
+	}
+	return ref.Hash().String()
+}
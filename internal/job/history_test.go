@@ -0,0 +1,63 @@
+package job
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	"github.com/isacikgoz/gitbatch/internal/history"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestQueueHistory(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	l := history.New(filepath.Join(dir, "history.jsonl"))
+	// a new commit on the remote is pulled
+	remote := filepath.Join(filepath.Dir(r.AbsPath), "src")
+	if out, err := command.Run(remote, "git", []string{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", "second commit"}); err != nil {
+		t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+	}
+	if out, err := command.Run(remote, "git", []string{"push", filepath.Join(filepath.Dir(r.AbsPath), "remote.git"), "HEAD"}); err != nil {
+		t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+	}
+	before := head(r)
+	q := CreateJobQueue()
+	q.SetHistory(l)
+	q.AddJob(&Job{
+		JobType:    PullJob,
+		Repository: r,
+		Options: &command.PullOptions{
+			RemoteName:  "origin",
+			CommandMode: command.ModeLegacy,
+		},
+	})
+	for _, err := range q.StartJobsAsync(context.Background()) {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	entries, err := l.Entries()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Test Failed. entries: %d, expected: %d", len(entries), 1)
+	}
+	e := entries[0]
+	if e.Job != string(PullJob) || e.Outcome != history.Succeeded || e.Path != r.AbsPath {
+		t.Errorf("Test Failed. job is not recorded correctly: %+v", e)
+	}
+	if e.Before != before || e.After != head(r) || !e.Changed() {
+		t.Errorf("Test Failed. before: %s, after: %s, expected a change from %s", e.Before, e.After, before)
+	}
+}
@@ -1,8 +1,15 @@
 package job
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
 )
 
 // Job relates the type of the operation and the entity
@@ -13,6 +20,14 @@ type Job struct {
 	Repository *git.Repository
 	// Options is a placeholder for operation options
 	Options interface{}
+	// Timeout is the maximum duration of the operation including its retries,
+	// zero means no limit
+	Timeout time.Duration
+	// Retry is the policy of trying the job again if it fails with a transient
+	// network error
+	Retry RetryPolicy
+
+	attempt int
 }
 
 // Type is the a git operation supported
@@ -30,15 +45,69 @@ const (
 
 	// CheckoutJob is wrapper of git merge command
 	CheckoutJob Type = "checkout"
+
+	// PushJob is wrapper of git push command
+	PushJob Type = "push"
+
+	// RebaseJob is wrapper of git rebase command, the current branch is
+	// rebased onto its upstream
+	RebaseJob Type = "rebase"
+
+	// UndoJob resets the repository to its state before a recorded job, its
+	// options has to be the *history.Entry of the job
+	UndoJob Type = "undo"
+
+	// CleanupJob deletes the stale branches of the repository, its options
+	// has to be the *CleanupOptions
+	CleanupJob Type = "cleanup"
 )
 
-// starts the job
-func (j *Job) start() error {
+// types are the job types that can be started
+var types = []Type{FetchJob, PullJob, MergeJob, CheckoutJob, PushJob, RebaseJob}
+
+// ParseType returns the job type with the given name
+func ParseType(s string) (Type, error) {
+	for _, t := range types {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized job type: %s", s)
+}
+
+// starts the job, it is tried again according to the retry policy
+func (j *Job) start(ctx context.Context) error {
+	if j.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.Timeout)
+		defer cancel()
+	}
+	for j.attempt = 1; ; j.attempt++ {
+		// the queue may be cancelled while the job is waiting for its turn
+		if err := ctx.Err(); err != nil {
+			return j.failed(ctx, err)
+		}
+		// the repository isn't refreshed by the watcher while the job runs
+		j.Repository.Lock()
+		err := j.run(ctx)
+		j.Repository.Unlock()
+		if !j.Retry.retryable(j.attempt, err) {
+			return err
+		}
+		if err := j.wait(ctx); err != nil {
+			return j.failed(ctx, err)
+		}
+	}
+}
+
+// runs a single attempt of the job
+func (j *Job) run(ctx context.Context) error {
 	j.Repository.SetWorkStatus(git.Working)
+	j.Repository.State.LastError = nil
 	// TODO: Better implementation required
 	switch mode := j.JobType; mode {
 	case FetchJob:
-		j.Repository.State.Message = "fetching.."
+		j.Repository.State.Message = j.progress("fetching..")
 		var opts *command.FetchOptions
 		if j.Options != nil {
 			opts = j.Options.(*command.FetchOptions)
@@ -48,18 +117,17 @@ func (j *Job) start() error {
 				CommandMode: command.ModeNative,
 			}
 		}
-		if err := command.Fetch(j.Repository, opts); err != nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = err.Error()
-			return err
+		if err := j.authenticate(ctx, opts.Credentials, func(c *git.Credentials) error {
+			opts.Credentials = c
+			return command.Fetch(ctx, j.Repository, opts)
+		}); err != nil {
+			return j.failed(ctx, err)
 		}
 	case PullJob:
-		j.Repository.State.Message = "pulling.."
+		j.Repository.State.Message = j.progress("pulling..")
 		var opts *command.PullOptions
 		if j.Repository.State.Branch.Upstream == nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = "upstream not set"
-			return nil
+			return j.failed(ctx, gerr.ErrRemoteBranchNotSpecified)
 		}
 		if j.Options != nil {
 			opts = j.Options.(*command.PullOptions)
@@ -69,27 +137,50 @@ func (j *Job) start() error {
 				CommandMode: command.ModeNative,
 			}
 		}
-		if err := command.Pull(j.Repository, opts); err != nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = err.Error()
-			return err
+		if opts.FFOnly && !opts.Rebase && j.Repository.State.Branch.Diverged() {
+			return j.failed(ctx, gerr.ErrDiverged)
+		}
+		if err := j.authenticate(ctx, opts.Credentials, func(c *git.Credentials) error {
+			opts.Credentials = c
+			return command.Pull(ctx, j.Repository, opts)
+		}); err != nil {
+			return j.failed(ctx, err)
 		}
 	case MergeJob:
-		j.Repository.State.Message = "merging.."
+		j.Repository.State.Message = j.progress("merging..")
 		if j.Repository.State.Branch.Upstream == nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = "upstream not set"
-			return nil
+			return j.failed(ctx, gerr.ErrRemoteBranchNotSpecified)
 		}
-		if err := command.Merge(j.Repository, &command.MergeOptions{
-			BranchName: j.Repository.State.Branch.Upstream.Name,
-		}); err != nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = err.Error()
-			return err
+		opts := command.MergeOptions{}
+		if j.Options != nil {
+			opts = *j.Options.(*command.MergeOptions)
+		}
+		if len(opts.BranchName) == 0 {
+			opts.BranchName = j.Repository.State.Branch.Upstream.Name
+		}
+		if opts.FFOnly && j.Repository.State.Branch.Diverged() {
+			return j.failed(ctx, gerr.ErrDiverged)
+		}
+		if err := command.Merge(ctx, j.Repository, &opts); err != nil {
+			return j.failed(ctx, err)
+		}
+	case RebaseJob:
+		j.Repository.State.Message = j.progress("rebasing..")
+		if j.Repository.State.Branch.Upstream == nil {
+			return j.failed(ctx, gerr.ErrRemoteBranchNotSpecified)
+		}
+		opts := command.RebaseOptions{}
+		if j.Options != nil {
+			opts = *j.Options.(*command.RebaseOptions)
+		}
+		if len(opts.Upstream) == 0 {
+			opts.Upstream = j.Repository.State.Branch.Upstream.Name
+		}
+		if err := command.Rebase(ctx, j.Repository, &opts); err != nil {
+			return j.failed(ctx, err)
 		}
 	case CheckoutJob:
-		j.Repository.State.Message = "switching to.."
+		j.Repository.State.Message = j.progress("switching to..")
 		var opts *command.CheckoutOptions
 		if j.Options != nil {
 			opts = j.Options.(*command.CheckoutOptions)
@@ -99,10 +190,36 @@ func (j *Job) start() error {
 				CommandMode: command.ModeNative,
 			}
 		}
-		if err := command.Checkout(j.Repository, opts); err != nil {
-			j.Repository.SetWorkStatus(git.Fail)
-			j.Repository.State.Message = err.Error()
-			return err
+		if err := command.Checkout(ctx, j.Repository, opts); err != nil {
+			return j.failed(ctx, err)
+		}
+	case PushJob:
+		j.Repository.State.Message = j.progress("pushing..")
+		var opts *command.PushOptions
+		if j.Options != nil {
+			opts = j.Options.(*command.PushOptions)
+		} else {
+			opts = &command.PushOptions{
+				RemoteName:  j.Repository.State.Remote.Name,
+				SetUpstream: j.Repository.State.Branch.Upstream == nil,
+				CommandMode: command.ModeNative,
+			}
+		}
+		if err := j.authenticate(ctx, opts.Credentials, func(c *git.Credentials) error {
+			opts.Credentials = c
+			return command.Push(ctx, j.Repository, opts)
+		}); err != nil {
+			return j.failed(ctx, err)
+		}
+	case UndoJob:
+		j.Repository.State.Message = j.progress("undoing..")
+		if err := j.undo(ctx, j.Options.(*history.Entry)); err != nil {
+			return j.failed(ctx, err)
+		}
+	case CleanupJob:
+		j.Repository.State.Message = j.progress("cleaning up..")
+		if err := j.cleanup(ctx, j.Options.(*CleanupOptions)); err != nil {
+			return j.failed(ctx, err)
 		}
 	default:
 		j.Repository.SetWorkStatus(git.Available)
@@ -110,3 +227,45 @@ func (j *Job) start() error {
 	}
 	return nil
 }
+
+// conflicted reports whether the error is caused by the conflicts of a merge or
+// rebase
+func conflicted(err error) bool {
+	return errors.Is(err, gerr.ErrRebaseConflict) ||
+		errors.Is(err, gerr.ErrConflictAfterMerge) ||
+		errors.Is(err, gerr.ErrUnmergedFiles)
+}
+
+// failed sets the state of the repository according to the error. If the
+// context is done, the job is considered as cancelled rather than failed
+func (j *Job) failed(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.Canceled:
+		j.Repository.State.Message = "cancelled"
+		j.Repository.SetWorkStatus(git.Cancelled)
+		return ctx.Err()
+	case context.DeadlineExceeded:
+		j.Repository.State.Message = "timed out after " + j.Timeout.String()
+		j.Repository.SetWorkStatus(git.Cancelled)
+		return ctx.Err()
+	}
+	j.Repository.State.Message = err.Error()
+	j.Repository.State.LastError = err
+	if j.attempt > 1 {
+		j.Repository.State.Message += fmt.Sprintf(" (after %d attempts)", j.attempt)
+	}
+	// the stopped merge or rebase waits for the conflicts to be resolved or
+	// aborted
+	if conflicted(err) && (j.Repository.Rebasing() || j.Repository.Merging()) {
+		j.Repository.SetWorkStatus(git.Conflicted)
+		return err
+	}
+	// the fast-forward or the deletion of the unmerged branches is refused
+	// before anything is changed
+	if errors.Is(err, gerr.ErrDiverged) || errors.Is(err, gerr.ErrBranchNotMerged) {
+		j.Repository.SetWorkStatus(git.Skipped)
+		return err
+	}
+	j.Repository.SetWorkStatus(git.Fail)
+	return err
+}
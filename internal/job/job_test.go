@@ -1,11 +1,17 @@
 package job
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
 	ggit "gopkg.in/src-d/go-git.v4"
 )
 
@@ -41,12 +47,79 @@ func TestStart(t *testing.T) {
 		{mockJob3},
 	}
 	for _, test := range tests {
-		if err := test.input.start(); err != nil {
+		if err := test.input.start(context.Background()); err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
 	}
 }
 
+func TestStartCancelled(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	j := &Job{
+		JobType:    FetchJob,
+		Repository: r,
+		Options: &command.FetchOptions{
+			RemoteName:  "origin",
+			CommandMode: command.ModeLegacy,
+		},
+	}
+	if err := j.start(ctx); err != context.Canceled {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, context.Canceled)
+	}
+	if r.WorkStatus() != git.Cancelled {
+		t.Errorf("Test Failed. repository is not marked as cancelled")
+	}
+}
+
+func TestStartTimeout(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	j := &Job{
+		JobType:    FetchJob,
+		Repository: r,
+		Options: &command.FetchOptions{
+			RemoteName:  "origin",
+			CommandMode: command.ModeLegacy,
+		},
+		Timeout: time.Nanosecond,
+	}
+	if err := j.start(context.Background()); err != context.DeadlineExceeded {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, context.DeadlineExceeded)
+	}
+	if r.WorkStatus() != git.Cancelled || !strings.HasPrefix(r.State.Message, "timed out") {
+		t.Errorf("Test Failed. status: %v, message: %s", r.WorkStatus(), r.State.Message)
+	}
+}
+
+func TestStartDiverged(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	r.State.Branch.Pushables, r.State.Branch.Pullables = 1, 2
+	j := &Job{
+		JobType:    MergeJob,
+		Repository: r,
+		Options:    &command.MergeOptions{FFOnly: true},
+	}
+	if err := j.start(context.Background()); err != gerr.ErrDiverged {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, gerr.ErrDiverged)
+	}
+	if r.WorkStatus() != git.Skipped {
+		t.Errorf("Test Failed. repository is not marked as skipped")
+	}
+}
+
 func testRepo() (*git.Repository, error) {
 	testRepoURL := "https://gitlab.com/isacikgoz/dirty-repo.git"
 	_, err := ggit.PlainClone(testRepoDir, false, &ggit.CloneOptions{
@@ -62,3 +135,22 @@ func testRepo() (*git.Repository, error) {
 func cleanRepo() error {
 	return os.RemoveAll(testRepoDir)
 }
+
+func TestParseType(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected Type
+		fail     bool
+	}{
+		{"fetch", FetchJob, false},
+		{"checkout", CheckoutJob, false},
+		{"push", PushJob, false},
+		{"stash", "", true},
+	}
+	for _, test := range tests {
+		output, err := ParseType(test.input)
+		if output != test.expected || (err != nil) != test.fail {
+			t.Errorf("Test Failed. %s inputted, output: %s, expected: %s", test.input, output, test.expected)
+		}
+	}
+}
@@ -5,14 +5,40 @@ import (
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
 	"golang.org/x/sync/semaphore"
 )
 
 // Queue holds the slice of Jobs
 type Queue struct {
 	series []*Job
+
+	// running jobs can be cancelled individually, the cancel func stops all
+	// of the jobs started by StartJobsAsync
+	running map[*Job]context.CancelFunc
+	cancel  context.CancelFunc
+	mutex   *sync.Mutex
+
+	limits Limits
+	hosts  map[string]*semaphore.Weighted
+
+	// the outcome of every job is recorded to the history with the batch id
+	// of the StartJobsAsync call that started it
+	history *history.Log
+	batch   string
+}
+
+// Limits defines how many jobs of a queue are allowed to run at the same time
+type Limits struct {
+	// Workers is the maximum number of concurrent jobs. Zero means the number
+	// of logical CPUs
+	Workers int
+	// PerHost is the maximum number of concurrent jobs against the same remote
+	// host. Zero means no limit
+	PerHost int
 }
 
 // CreateJobQueue creates a jobqueue struct and initialize its slice then return
@@ -20,12 +46,26 @@ type Queue struct {
 func CreateJobQueue() (jq *Queue) {
 	s := make([]*Job, 0)
 	return &Queue{
-		series: s,
+		series:  s,
+		running: make(map[*Job]context.CancelFunc),
+		mutex:   &sync.Mutex{},
+		hosts:   make(map[string]*semaphore.Weighted),
 	}
 }
 
+// SetLimits sets the concurrency limits of the queue, it takes effect on the
+// next StartJobsAsync call
+func (jq *Queue) SetLimits(l Limits) {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
+	jq.limits = l
+	jq.hosts = make(map[string]*semaphore.Weighted)
+}
+
 // AddJob adds a job to the queue
 func (jq *Queue) AddJob(j *Job) error {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
 	for _, job := range jq.series {
 		if job.Repository.RepoID == j.Repository.RepoID && job.JobType == j.JobType {
 			return fmt.Errorf("same job already is in the queue")
@@ -38,31 +78,97 @@ func (jq *Queue) AddJob(j *Job) error {
 }
 
 // StartNext starts the next job in the queue
-func (jq *Queue) StartNext() (j *Job, finished bool, err error) {
+func (jq *Queue) StartNext(ctx context.Context) (j *Job, finished bool, err error) {
 	finished = false
+	jq.mutex.Lock()
 	if len(jq.series) < 1 {
+		jq.mutex.Unlock()
 		finished = true
 		return nil, finished, nil
 	}
-	i := len(jq.series) - 1
+	i, host, acquired := jq.next()
 	lastJob := jq.series[i]
-	jq.series = jq.series[:i]
-	if err = lastJob.start(); err != nil {
+	jq.series = append(jq.series[:i], jq.series[i+1:]...)
+	ctx, cancel := context.WithCancel(ctx)
+	jq.running[lastJob] = cancel
+	jq.mutex.Unlock()
+
+	defer func() {
+		jq.mutex.Lock()
+		delete(jq.running, lastJob)
+		jq.mutex.Unlock()
+		cancel()
+	}()
+	if host != nil {
+		// every host in the queue is busy, wait for the host of this job. If
+		// the context is done meanwhile, the job will notice it on start
+		if !acquired && host.Acquire(ctx, 1) == nil {
+			acquired = true
+		}
+		if acquired {
+			defer host.Release(1)
+		}
+	}
+	before, started := snapshot(lastJob.Repository), time.Now()
+	err = lastJob.start(ctx)
+	jq.record(lastJob, before, started, err)
+	if err != nil {
 		return lastJob, finished, err
 	}
 	return lastJob, finished, nil
 }
 
-// RemoveFromQueue deletes the given entity and its job from the queue
-// TODO: it is not safe if the job has been started
+// next returns the index of the job that should be started next. Normally it is
+// the oldest one but the jobs whose remote host is already at its limit are
+// skipped if possible. The host semaphore of the job is returned too, acquired
+// reports whether a slot is already taken from it. Caller must hold the lock.
+func (jq *Queue) next() (i int, host *semaphore.Weighted, acquired bool) {
+	last := len(jq.series) - 1
+	if jq.limits.PerHost <= 0 {
+		return last, nil, false
+	}
+	for i := last; i >= 0; i-- {
+		sem := jq.hostSemaphore(jq.series[i])
+		if sem == nil || sem.TryAcquire(1) {
+			return i, sem, true
+		}
+	}
+	return last, jq.hostSemaphore(jq.series[last]), false
+}
+
+// hostSemaphore returns the semaphore of the job's remote host, nil if there
+// is no host to limit. Caller must hold the lock.
+func (jq *Queue) hostSemaphore(j *Job) *semaphore.Weighted {
+	h, err := git.RemoteHost(j.Repository.State.Remote)
+	if err != nil || len(h) == 0 {
+		return nil
+	}
+	if sem, ok := jq.hosts[h]; ok {
+		return sem
+	}
+	sem := semaphore.NewWeighted(int64(jq.limits.PerHost))
+	jq.hosts[h] = sem
+	return sem
+}
+
+// RemoveFromQueue deletes the given entity and its job from the queue. If the
+// job of the entity has already been started, it is cancelled
 func (jq *Queue) RemoveFromQueue(r *git.Repository) error {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
 	removed := false
-	for i, job := range jq.series {
-		if job.Repository.RepoID == r.RepoID {
+	for i := len(jq.series) - 1; i >= 0; i-- {
+		if jq.series[i].Repository.RepoID == r.RepoID {
 			jq.series = append(jq.series[:i], jq.series[i+1:]...)
 			removed = true
 		}
 	}
+	for job, cancel := range jq.running {
+		if job.Repository.RepoID == r.RepoID {
+			cancel()
+			removed = true
+		}
+	}
 	if !removed {
 		return fmt.Errorf("there is no job with given repoID")
 	}
@@ -73,6 +179,8 @@ func (jq *Queue) RemoveFromQueue(r *git.Repository) error {
 // struct, this function returns true if that entity is in the queue along with
 // the jobs type
 func (jq *Queue) IsInTheQueue(r *git.Repository) (inTheQueue bool, j *Job) {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
 	inTheQueue = false
 	for _, job := range jq.series {
 		if job.Repository.RepoID == r.RepoID {
@@ -83,19 +191,50 @@ func (jq *Queue) IsInTheQueue(r *git.Repository) (inTheQueue bool, j *Job) {
 	return inTheQueue, j
 }
 
-// StartJobsAsync start he jobs in the queue asynchronously
-func (jq *Queue) StartJobsAsync() map[*Job]error {
+// Jobs returns the jobs that are waiting in the queue
+func (jq *Queue) Jobs() []*Job {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
+	jobs := make([]*Job, len(jq.series))
+	copy(jobs, jq.series)
+	return jobs
+}
+
+// Cancel stops the running jobs and the ones that are waiting in the queue.
+// The running git processes are killed.
+func (jq *Queue) Cancel() {
+	jq.mutex.Lock()
+	defer jq.mutex.Unlock()
+	if jq.cancel != nil {
+		jq.cancel()
+	}
+	for _, cancel := range jq.running {
+		cancel()
+	}
+}
 
-	ctx := context.TODO()
+// StartJobsAsync start he jobs in the queue asynchronously. The jobs are
+// stopped if the context is done or the queue is cancelled.
+func (jq *Queue) StartJobsAsync(ctx context.Context) map[*Job]error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jq.mutex.Lock()
+	jq.cancel = cancel
+	jq.batch = newBatchID()
+	count := len(jq.series)
+	maxWorkers := jq.limits.Workers
+	jq.mutex.Unlock()
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0)
+	}
 
 	var (
-		maxWorkers = runtime.GOMAXPROCS(0)
-		sem        = semaphore.NewWeighted(int64(maxWorkers))
-		fails      = make(map[*Job]error)
+		sem   = semaphore.NewWeighted(int64(maxWorkers))
+		fails = make(map[*Job]error)
 	)
 
 	var mx sync.Mutex
-	for range jq.series {
+	for i := 0; i < count; i++ {
 
 		if err := sem.Acquire(ctx, 1); err != nil {
 			break
@@ -104,7 +243,7 @@ func (jq *Queue) StartJobsAsync() map[*Job]error {
 		go func() {
 
 			defer sem.Release(1)
-			j, _, err := jq.StartNext()
+			j, _, err := jq.StartNext(ctx)
 			if err != nil {
 				mx.Lock()
 				fails[j] = err
@@ -112,6 +251,22 @@ func (jq *Queue) StartJobsAsync() map[*Job]error {
 			}
 		}()
 	}
-	sem.Acquire(ctx, int64(maxWorkers))
+	// wait for the workers even if the context is done, they will return
+	// as soon as their processes are killed
+	sem.Acquire(context.Background(), int64(maxWorkers))
+
+	// the jobs that couldn't find a chance to start are cancelled too
+	if err := ctx.Err(); err != nil {
+		jq.mutex.Lock()
+		remaining := jq.series
+		jq.series = make([]*Job, 0)
+		jq.mutex.Unlock()
+		for _, j := range remaining {
+			j.Repository.State.Message = "cancelled"
+			j.Repository.SetWorkStatus(git.Cancelled)
+			fails[j] = err
+			jq.record(j, snapshot(j.Repository), time.Now(), err)
+		}
+	}
 	return fails
 }
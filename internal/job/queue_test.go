@@ -1,9 +1,12 @@
 package job
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
 )
 
 func TestCreateJobQueue(t *testing.T) {
@@ -89,8 +92,83 @@ func TestStartJobsAsync(t *testing.T) {
 		{q},
 	}
 	for _, test := range tests {
-		if output := test.input.StartJobsAsync(); len(output) != 0 {
+		if output := test.input.StartJobsAsync(context.Background()); len(output) != 0 {
 			t.Errorf("Test Failed.")
 		}
 	}
 }
+
+func TestCancel(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	q := CreateJobQueue()
+	q.AddJob(&Job{JobType: FetchJob, Repository: r})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fails := q.StartJobsAsync(ctx)
+	if len(fails) != 1 {
+		t.Errorf("Test Failed. %d failures, expected: 1", len(fails))
+	}
+	if r.WorkStatus() != git.Cancelled {
+		t.Errorf("Test Failed. repository is not marked as cancelled")
+	}
+	if in, _ := q.IsInTheQueue(r); in {
+		t.Errorf("Test Failed. cancelled job is still in the queue")
+	}
+}
+
+func TestRemoveRunningFromQueue(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	q := CreateJobQueue()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := &Job{JobType: FetchJob, Repository: r}
+	q.running[j] = cancel
+	if err := q.RemoveFromQueue(r); err != nil {
+		t.Errorf("Test Failed. error: %s", err.Error())
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("Test Failed. running job is not cancelled")
+	}
+}
+
+func TestNextWithHostLimit(t *testing.T) {
+	remote := func(url string) *git.Repository {
+		return &git.Repository{
+			RepoID: url,
+			State:  &git.RepositoryState{Remote: &git.Remote{URL: []string{url}}},
+		}
+	}
+	a := &Job{Repository: remote("https://a.com/first.git")}
+	b := &Job{Repository: remote("https://a.com/second.git")}
+	c := &Job{Repository: remote("git@b.com:third.git")}
+	q := CreateJobQueue()
+	q.SetLimits(Limits{PerHost: 1})
+	for _, j := range []*Job{a, b, c} {
+		q.AddJob(j)
+	}
+	var tests = []struct {
+		expected *Job
+		acquired bool
+	}{
+		{a, true},
+		{c, true},
+		{b, false},
+	}
+	for _, test := range tests {
+		i, _, acquired := q.next()
+		if q.series[i] != test.expected || acquired != test.acquired {
+			t.Errorf("Test Failed. output: {%s, %t}, expected: {%s, %t}", q.series[i].Repository.RepoID, acquired, test.expected.Repository.RepoID, test.acquired)
+		}
+		q.series = append(q.series[:i], q.series[i+1:]...)
+	}
+}
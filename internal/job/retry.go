@@ -0,0 +1,68 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// RetryPolicy defines how a job that failed with a transient network error is
+// tried again. The zero value doesn't retry at all.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it is doubled on every
+	// retry
+	Backoff time.Duration
+	// MaxBackoff limits the delay, zero means no limit
+	MaxBackoff time.Duration
+}
+
+// retryable reports whether the job should be tried again after the attempt
+// failed with the error. Authentication failures are never retried.
+func (p RetryPolicy) retryable(attempt int, err error) bool {
+	return attempt < p.MaxAttempts && gerr.IsTransient(err)
+}
+
+// delay returns the duration to wait after the attempt. It is a random duration
+// between the half and the whole of the exponential backoff, so that the jobs
+// failed at the same time don't hit the remote at the same time again.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff << uint(attempt-1)
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// wait blocks until the next attempt of the job, the retry is shown on the
+// status of the repository meanwhile
+func (j *Job) wait(ctx context.Context) error {
+	d := j.Retry.delay(j.attempt)
+	j.Repository.State.Message = fmt.Sprintf("retrying in %s (%d/%d)", d.Round(time.Millisecond), j.attempt, j.Retry.MaxAttempts-1)
+	j.Repository.SetWorkStatus(git.Working)
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// progress returns the status message of the running job, the retry count is
+// added if it is not the first attempt
+func (j *Job) progress(msg string) string {
+	if j.attempt > 1 {
+		return fmt.Sprintf("%s (retry %d/%d)", msg, j.attempt-1, j.Retry.MaxAttempts-1)
+	}
+	return msg
+}
@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestRetryable(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3}
+	var tests = []struct {
+		attempt  int
+		err      error
+		expected bool
+	}{
+		{1, nil, false},
+		{1, gerr.ErrConnectionFailed, true},
+		{2, gerr.ErrConnectionFailed, true},
+		{3, gerr.ErrConnectionFailed, false},
+		{1, gerr.ErrAuthenticationRequired, false},
+		{1, errors.New("repository not found"), false},
+	}
+	for _, test := range tests {
+		if output := p.retryable(test.attempt, test.err); output != test.expected {
+			t.Errorf("Test Failed. attempt %d with %v, output: %t, expected: %t", test.attempt, test.err, output, test.expected)
+		}
+	}
+	if (RetryPolicy{}).retryable(1, gerr.ErrConnectionFailed) {
+		t.Errorf("Test Failed. zero policy retries")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	var tests = []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 300 * time.Millisecond},
+		{64, 300 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if output := p.delay(test.attempt); output < test.max/2 || output > test.max {
+			t.Errorf("Test Failed. attempt %d, output: %s, expected between %s and %s", test.attempt, output, test.max/2, test.max)
+		}
+	}
+}
+
+func TestStartRetry(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	// nothing listens on the port, the connection is refused
+	if out, err := command.Run(r.AbsPath, "git", []string{"remote", "set-url", "origin", "http://127.0.0.1:1/dirty-repo.git"}); err != nil {
+		t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+	}
+	j := &Job{
+		JobType:    FetchJob,
+		Repository: r,
+		Options: &command.FetchOptions{
+			RemoteName:  "origin",
+			CommandMode: command.ModeLegacy,
+		},
+		Retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+	}
+	if err := j.start(context.Background()); !errors.Is(err, gerr.ErrConnectionFailed) {
+		t.Errorf("Test Failed. error: %v, expected: %v", err, gerr.ErrConnectionFailed)
+	}
+	if r.WorkStatus() != git.Fail || !strings.HasSuffix(r.State.Message, "(after 3 attempts)") {
+		t.Errorf("Test Failed. status: %v, message: %s", r.WorkStatus(), r.State.Message)
+	}
+}
@@ -0,0 +1,82 @@
+package job
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
+)
+
+// Undo returns the jobs that reset the repositories back to their state before
+// the batch. Only the jobs that moved HEAD or switched the branch are undone
+// and the repositories are matched by their paths.
+func Undo(b *history.Batch, repositories []*git.Repository) []*Job {
+	paths := make(map[string]*git.Repository)
+	for _, r := range repositories {
+		paths[r.AbsPath] = r
+	}
+	jobs := make([]*Job, 0)
+	for _, e := range b.Undoable() {
+		if r, ok := paths[e.Path]; ok {
+			jobs = append(jobs, &Job{
+				JobType:    UndoJob,
+				Repository: r,
+				Options:    e,
+			})
+		}
+	}
+	return jobs
+}
+
+// undo resets the repository to its state before the recorded job. It is
+// refused if the repository has moved since the job or it has local changes.
+func (j *Job) undo(ctx context.Context, e *history.Entry) error {
+	r := j.Repository
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// the state may be stale if the repository is changed outside
+	if err := r.Refresh(); err != nil {
+		return err
+	}
+	branch := e.Branch
+	if len(e.BranchAfter) > 0 {
+		branch = e.BranchAfter
+	}
+	if current := snapshot(r); current.branch != branch || current.head != e.After {
+		return gerr.ErrHeadMoved
+	}
+	if !r.State.Branch.Clean {
+		return gerr.ErrMergeAbortedTryCommit
+	}
+	if branch != e.Branch {
+		// the previous branch should be left as it was
+		ref, err := r.Repo.Reference(plumbing.NewBranchReferenceName(e.Branch), true)
+		if err != nil || ref.Hash().String() != e.Before {
+			return gerr.ErrHeadMoved
+		}
+		if err := command.Checkout(ctx, r, &command.CheckoutOptions{
+			TargetRef: e.Branch,
+		}); err != nil {
+			return err
+		}
+		if r.WorkStatus() == git.Fail {
+			return gerr.ErrUnclassified
+		}
+	} else if err := command.ResetAll(r, &command.ResetOptions{
+		Hash:        e.Before,
+		ResetType:   command.ResetKeep,
+		CommandMode: command.ModeLegacy,
+	}); err != nil {
+		return err
+	}
+	r.SetWorkStatus(git.Success)
+	r.State.Message = "reset to " + e.Before[:7]
+	if branch != e.Branch {
+		r.State.Message = "switched back to " + e.Branch
+	}
+	return r.Refresh()
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	gerr "github.com/isacikgoz/gitbatch/internal/errors"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/history"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestUndo(t *testing.T) {
+	var tests = []struct {
+		name     string
+		change   []string
+		expected error
+	}{
+		{"unchanged", nil, nil},
+		{"moved", []string{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", "local commit"}, gerr.ErrHeadMoved},
+		{"switched", []string{"checkout", "-b", "other"}, gerr.ErrHeadMoved},
+	}
+	for _, test := range tests {
+		r, l, cleanup, err := testPulledRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		entries, err := l.Entries()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		before := entries[0].Before
+		if test.change != nil {
+			if out, err := command.Run(r.AbsPath, "git", test.change); err != nil {
+				t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+			}
+		}
+		b := history.LastUndoable(history.Batches(entries))
+		if b == nil {
+			t.Fatalf("Test Failed. %s: there is no batch to undo", test.name)
+		}
+		q := CreateJobQueue()
+		q.SetHistory(l)
+		for _, j := range Undo(b, []*git.Repository{r}) {
+			q.AddJob(j)
+		}
+		err = nil
+		for _, ferr := range q.StartJobsAsync(context.Background()) {
+			err = ferr
+		}
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Test Failed. %s: error: %v, expected: %v", test.name, err, test.expected)
+		}
+		if test.expected == nil {
+			if head(r) != before {
+				t.Errorf("Test Failed. %s: HEAD is not reset", test.name)
+			}
+			entries, _ = l.Entries()
+			if history.LastUndoable(history.Batches(entries)) != nil {
+				t.Errorf("Test Failed. %s: the batch is undoable after undo", test.name)
+			}
+		}
+		cleanup()
+	}
+}
+
+// testPulledRepo creates a repository that has pulled a new commit from its
+// remote, the pull is recorded to the returned history
+func testPulledRepo() (*git.Repository, *history.Log, func(), error) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, err
+	}
+	root := filepath.Dir(r.AbsPath)
+	l := history.New(filepath.Join(dir, "history.jsonl"))
+	all := func() {
+		cleanup()
+		os.RemoveAll(dir)
+	}
+	steps := []struct {
+		dir  string
+		args []string
+	}{
+		{filepath.Join(root, "src"), []string{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", "second commit"}},
+		{filepath.Join(root, "src"), []string{"push", filepath.Join(root, "remote.git"), "HEAD"}},
+	}
+	for _, step := range steps {
+		if _, err := command.Run(step.dir, "git", step.args); err != nil {
+			all()
+			return nil, nil, nil, err
+		}
+	}
+	q := CreateJobQueue()
+	q.SetHistory(l)
+	q.AddJob(&Job{
+		JobType:    PullJob,
+		Repository: r,
+		Options: &command.PullOptions{
+			RemoteName:  "origin",
+			CommandMode: command.ModeLegacy,
+		},
+	})
+	for _, err := range q.StartJobsAsync(context.Background()) {
+		all()
+		return nil, nil, nil, err
+	}
+	return r, l, all, nil
+}
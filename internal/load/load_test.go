@@ -53,11 +53,12 @@ func TestAsyncLoad(t *testing.T) {
 		inp1 []string
 		inp2 AsyncAdd
 		inp3 chan bool
+		inp4 int
 	}{
-		{[]string{basic, dirty}, testAsyncMockFunc, testChannel},
+		{[]string{basic, dirty}, testAsyncMockFunc, testChannel, 0},
 	}
 	for _, test := range tests {
-		err := AsyncLoad(test.inp1, test.inp2, test.inp3)
+		err := AsyncLoad(test.inp1, test.inp2, test.inp3, test.inp4)
 		if err != nil {
 			t.Errorf("Test Failed. error: %s", err.Error())
 		}
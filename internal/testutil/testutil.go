@@ -0,0 +1,58 @@
+// Package testutil provides the repository fixtures that are shared by the
+// tests of the other packages, so that they don't need the network
+package testutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// LocalRemote creates a bare repository with an initial commit and its clone
+// in a temporary directory. The bare repository is "remote.git" and the clone
+// is "local" in the returned directory, it has to be removed by the caller.
+func LocalRemote() (string, error) {
+	root, err := ioutil.TempDir("", "local-remote")
+	if err != nil {
+		return "", err
+	}
+	src := filepath.Join(root, "src")
+	steps := []struct {
+		dir  string
+		args []string
+	}{
+		{root, []string{"init", src}},
+		{src, []string{"-c", "user.name=gitbatch", "-c", "user.email=gitbatch@localhost", "commit", "--allow-empty", "-m", "initial commit"}},
+		{root, []string{"clone", "--bare", src, "remote.git"}},
+		{root, []string{"clone", "remote.git", "local"}},
+	}
+	for _, step := range steps {
+		cmd := exec.Command("git", step.args...)
+		cmd.Dir = step.dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			os.RemoveAll(root)
+			return "", fmt.Errorf("%s: %s", err, out)
+		}
+	}
+	return root, nil
+}
+
+// LocalRepo initializes the clone of a LocalRemote, cleanup removes both of
+// them
+func LocalRepo() (r *git.Repository, cleanup func(), err error) {
+	root, err := LocalRemote()
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() { os.RemoveAll(root) }
+	r, err = git.InitializeRepo(filepath.Join(root, "local"))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return r, cleanup, nil
+}
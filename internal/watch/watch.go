@@ -0,0 +1,238 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/isacikgoz/gitbatch/internal/git"
+)
+
+// Watcher refreshes the repositories when their HEAD, refs or index is changed
+// by another process. The changes are watched with the file system events
+// if possible, otherwise the files are polled.
+type Watcher struct {
+	// Debounce is the duration to wait for the changes to settle down before
+	// refreshing the repository
+	Debounce time.Duration
+	// Interval is the polling interval of the repositories that can't be
+	// watched with file system events
+	Interval time.Duration
+
+	fsw     *fsnotify.Watcher
+	dirs    map[string]*git.Repository
+	polled  map[*git.Repository]string
+	timers  map[*git.Repository]*time.Timer
+	busy    map[*git.Repository]bool
+	quiet   map[*git.Repository]time.Time
+	refresh func(r *git.Repository) error
+	done    chan struct{}
+	mutex   *sync.Mutex
+}
+
+// New creates a watcher and starts listening the changes
+func New(debounce, interval time.Duration) *Watcher {
+	w := &Watcher{
+		Debounce: debounce,
+		Interval: interval,
+		dirs:     make(map[string]*git.Repository),
+		polled:   make(map[*git.Repository]string),
+		timers:   make(map[*git.Repository]*time.Timer),
+		busy:     make(map[*git.Repository]bool),
+		quiet:    make(map[*git.Repository]time.Time),
+		refresh:  (*git.Repository).Refresh,
+		done:     make(chan struct{}),
+		mutex:    &sync.Mutex{},
+	}
+	// if the platform doesn't support the events, everything is polled
+	if fsw, err := fsnotify.NewWatcher(); err == nil {
+		w.fsw = fsw
+		go w.listen()
+	}
+	go w.poll()
+	return w
+}
+
+// Add starts watching the repository
+func (w *Watcher) Add(r *git.Repository) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.fsw != nil {
+		if err := w.watchDirectories(r); err == nil {
+			return nil
+		}
+		// probably the watch limit is reached, poll it instead
+	}
+	w.polled[r] = signature(r)
+	return nil
+}
+
+// Close stops watching the repositories
+func (w *Watcher) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	close(w.done)
+	for _, t := range w.timers {
+		t.Stop()
+	}
+	if w.fsw != nil {
+		return w.fsw.Close()
+	}
+	return nil
+}
+
+// the git directory itself is watched for HEAD, index and packed-refs and the
+// refs directory is watched recursively since fsnotify is not recursive
+func (w *Watcher) watchDirectories(r *git.Repository) error {
+	gitDir := filepath.Join(r.AbsPath, ".git")
+	dirs := []string{gitDir}
+	filepath.Walk(filepath.Join(gitDir, "refs"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for _, d := range dirs {
+		if err := w.fsw.Add(d); err != nil {
+			for _, added := range dirs {
+				w.fsw.Remove(added)
+				delete(w.dirs, added)
+			}
+			return err
+		}
+		w.dirs[d] = r
+	}
+	return nil
+}
+
+func (w *Watcher) listen() {
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *Watcher) handle(event fsnotify.Event) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	// lock files are created and removed on every write, the actual file is
+	// renamed afterwards which triggers another event
+	if strings.HasSuffix(event.Name, ".lock") {
+		return
+	}
+	r, ok := w.dirs[filepath.Dir(event.Name)]
+	if !ok {
+		return
+	}
+	// a new branch with a slash in its name creates a directory under refs
+	if event.Op&fsnotify.Create == fsnotify.Create {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.fsw.Add(event.Name); err == nil {
+				w.dirs[event.Name] = r
+			}
+		}
+	}
+	// refreshing the repository may write the index, skip our own changes
+	if w.busy[r] || time.Now().Before(w.quiet[r]) {
+		return
+	}
+	w.schedule(r)
+}
+
+// poll checks the repositories that can't be watched with events
+func (w *Watcher) poll() {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.mutex.Lock()
+			for r, s := range w.polled {
+				if ns := signature(r); ns != s && !w.busy[r] {
+					w.polled[r] = ns
+					w.schedule(r)
+				}
+			}
+			w.mutex.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// schedule refreshes the repository after the debounce duration. If another
+// change occurs meanwhile, the refresh is postponed. Caller must hold the lock.
+func (w *Watcher) schedule(r *git.Repository) {
+	if t, ok := w.timers[r]; ok {
+		t.Reset(w.Debounce)
+		return
+	}
+	w.timers[r] = time.AfterFunc(w.Debounce, func() {
+		w.fire(r)
+	})
+}
+
+// fire refreshes the repository unless a job is going to work or already
+// working on it, the job refreshes the repository once it finishes anyway. The
+// repository is held during the refresh so that a job can't start meanwhile.
+func (w *Watcher) fire(r *git.Repository) {
+	w.mutex.Lock()
+	delete(w.timers, r)
+	w.busy[r] = true
+	w.mutex.Unlock()
+
+	r.Lock()
+	switch r.WorkStatus() {
+	case git.Queued, git.Working, git.Paused:
+	default:
+		w.refresh(r)
+	}
+	r.Unlock()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.busy[r] = false
+	w.quiet[r] = time.Now().Add(w.Debounce)
+	if _, ok := w.polled[r]; ok {
+		w.polled[r] = signature(r)
+	}
+}
+
+// signature is the summary of the modification times and sizes of the files
+// that matter, it changes if any of them changes
+func signature(r *git.Repository) string {
+	gitDir := filepath.Join(r.AbsPath, ".git")
+	var sb strings.Builder
+	stat := func(path string, info os.FileInfo) {
+		sb.WriteString(path)
+		sb.WriteString(info.ModTime().String())
+		sb.WriteString(strconv.FormatInt(info.Size(), 10))
+	}
+	for _, name := range []string{"HEAD", "index", "packed-refs"} {
+		if info, err := os.Stat(filepath.Join(gitDir, name)); err == nil {
+			stat(name, info)
+		}
+	}
+	filepath.Walk(filepath.Join(gitDir, "refs"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			stat(path, info)
+		}
+		return nil
+	})
+	return sb.String()
+}
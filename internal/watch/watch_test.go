@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isacikgoz/gitbatch/internal/command"
+	"github.com/isacikgoz/gitbatch/internal/git"
+	"github.com/isacikgoz/gitbatch/internal/testutil"
+)
+
+func TestWatcher(t *testing.T) {
+	var tests = []struct {
+		name string
+		poll bool
+	}{
+		{"events", false},
+		{"polling", true},
+	}
+	for _, test := range tests {
+		r, cleanup, err := testutil.LocalRepo()
+		if err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		w := New(50*time.Millisecond, 50*time.Millisecond)
+		var count int32
+		w.refresh = func(r *git.Repository) error {
+			atomic.AddInt32(&count, 1)
+			return nil
+		}
+		if test.poll {
+			w.mutex.Lock()
+			w.polled[r] = signature(r)
+			w.mutex.Unlock()
+		} else if err := w.Add(r); err != nil {
+			t.Fatalf("Test Failed. error: %s", err.Error())
+		}
+		// the branch is created by another process
+		if out, err := command.Run(r.AbsPath, "git", []string{"branch", "feature/watch"}); err != nil {
+			t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&count) == 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if atomic.LoadInt32(&count) == 0 {
+			t.Errorf("Test Failed. %s: repository is not refreshed", test.name)
+		}
+		w.Close()
+		cleanup()
+	}
+}
+
+func TestFire(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	w := New(time.Hour, time.Hour)
+	defer w.Close()
+	var tests = []struct {
+		status    git.WorkStatus
+		refreshed bool
+	}{
+		{git.Queued, false},
+		{git.Working, false},
+		{git.Paused, false},
+		{git.Available, true},
+	}
+	for _, test := range tests {
+		refreshed := false
+		w.refresh = func(r *git.Repository) error {
+			refreshed = true
+			return nil
+		}
+		r.SetWorkStatus(test.status)
+		w.fire(r)
+		if refreshed != test.refreshed {
+			t.Errorf("Test Failed. status: %v, refreshed: %t, expected: %t", test.status, refreshed, test.refreshed)
+		}
+	}
+}
+
+func TestSignature(t *testing.T) {
+	r, cleanup, err := testutil.LocalRepo()
+	if err != nil {
+		t.Fatalf("Test Failed. error: %s", err.Error())
+	}
+	defer cleanup()
+	s := signature(r)
+	if s != signature(r) {
+		t.Errorf("Test Failed. signature changed without any change")
+	}
+	if out, err := command.Run(r.AbsPath, "git", []string{"branch", "other"}); err != nil {
+		t.Fatalf("Test Failed. error: %s: %s", err.Error(), out)
+	}
+	if s == signature(r) {
+		t.Errorf("Test Failed. signature did not change after a new branch")
+	}
+}